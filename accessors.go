@@ -0,0 +1,47 @@
+package jsonschema
+
+import "encoding/json"
+
+// The accessors return the value of an optional keyword, handling the nil pointer
+// of an absent keyword. The numeric keywords are parsed as float64, their second
+// return value is false if the keyword is absent or not a valid number.
+
+func (s *Schema) MultipleOfFloat() (float64, bool)       { return numberFloat(s.MultipleOf) }
+func (s *Schema) MaximumFloat() (float64, bool)          { return numberFloat(s.Maximum) }
+func (s *Schema) ExclusiveMaximumFloat() (float64, bool) { return numberFloat(s.ExclusiveMaximum) }
+func (s *Schema) MinimumFloat() (float64, bool)          { return numberFloat(s.Minimum) }
+func (s *Schema) ExclusiveMinimumFloat() (float64, bool) { return numberFloat(s.ExclusiveMinimum) }
+
+func (s *Schema) MaxLengthOr(def int) int     { return valueOr(s.MaxLength, def) }
+func (s *Schema) MinLengthOr(def int) int     { return valueOr(s.MinLength, def) }
+func (s *Schema) MaxItemsOr(def int) int      { return valueOr(s.MaxItems, def) }
+func (s *Schema) MinItemsOr(def int) int      { return valueOr(s.MinItems, def) }
+func (s *Schema) MaxContainsOr(def int) int   { return valueOr(s.MaxContains, def) }
+func (s *Schema) MinContainsOr(def int) int   { return valueOr(s.MinContains, def) }
+func (s *Schema) MaxPropertiesOr(def int) int { return valueOr(s.MaxProperties, def) }
+func (s *Schema) MinPropertiesOr(def int) int { return valueOr(s.MinProperties, def) }
+
+func (s *Schema) UniqueItemsOr(def bool) bool { return valueOr(s.UniqueItems, def) }
+func (s *Schema) DeprecatedOr(def bool) bool  { return valueOr(s.Deprecated, def) }
+func (s *Schema) ReadOnlyOr(def bool) bool    { return valueOr(s.ReadOnly, def) }
+func (s *Schema) WriteOnlyOr(def bool) bool   { return valueOr(s.WriteOnly, def) }
+
+func (s *Schema) PatternOr(def string) string          { return valueOr(s.Pattern, def) }
+func (s *Schema) FormatOr(def string) string           { return valueOr(s.Format, def) }
+func (s *Schema) ContentEncodingOr(def string) string  { return valueOr(s.ContentEncoding, def) }
+func (s *Schema) ContentMediaTypeOr(def string) string { return valueOr(s.ContentMediaType, def) }
+
+func valueOr[T any](p *T, def T) T {
+	if p == nil {
+		return def
+	}
+	return *p
+}
+
+func numberFloat(n *json.Number) (float64, bool) {
+	if n == nil {
+		return 0, false
+	}
+	f, err := n.Float64()
+	return f, err == nil
+}
@@ -0,0 +1,46 @@
+package jsonschema_test
+
+import (
+	"encoding/json"
+	. "jsonschema"
+	"testing"
+)
+
+func TestSchema_Accessors(t *testing.T) {
+	s := &Schema{
+		Minimum:     ptr(json.Number("1.5")),
+		Maximum:     ptr(json.Number("x")),
+		MaxLength:   ptr(0),
+		UniqueItems: ptr(false),
+		Format:      ptr("date"),
+	}
+
+	if f, ok := s.MinimumFloat(); f != 1.5 || !ok {
+		t.Errorf("MinimumFloat: have %v, %t", f, ok)
+	}
+	if f, ok := s.MaximumFloat(); f != 0 || ok {
+		t.Errorf("MaximumFloat: have %v, %t", f, ok)
+	}
+	if f, ok := s.ExclusiveMinimumFloat(); f != 0 || ok {
+		t.Errorf("ExclusiveMinimumFloat: have %v, %t", f, ok)
+	}
+
+	if n := s.MaxLengthOr(10); n != 0 {
+		t.Errorf("MaxLengthOr: have %d, need 0", n)
+	}
+	if n := s.MinLengthOr(10); n != 10 {
+		t.Errorf("MinLengthOr: have %d, need 10", n)
+	}
+	if b := s.UniqueItemsOr(true); b {
+		t.Errorf("UniqueItemsOr: have %t, need false", b)
+	}
+	if b := s.DeprecatedOr(true); !b {
+		t.Errorf("DeprecatedOr: have %t, need true", b)
+	}
+	if f := s.FormatOr("uri"); f != "date" {
+		t.Errorf("FormatOr: have %q, need %q", f, "date")
+	}
+	if p := s.PatternOr(".*"); p != ".*" {
+		t.Errorf("PatternOr: have %q, need %q", p, ".*")
+	}
+}
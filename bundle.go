@@ -0,0 +1,126 @@
+package jsonschema
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// Unbundle splits root into separate schema documents, one for root and one for
+// every embedded schema resource, i.e. every subschema with an $id. The documents
+// are returned by their base URI, the $id of every document is made absolute.
+//
+// An embedded resource is replaced by a reference to its document. References
+// pointing into an embedded resource using a JSON pointer relative to an enclosing
+// resource are rewritten to reference the document of the embedded resource.
+//
+//	{"$id": "https://example.com/a.json", "$defs": {"b": {"$id": "b.json"}}}
+//
+// results in
+//
+//	"https://example.com/a.json": {"$id": "https://example.com/a.json", "$defs": {"b": {"$ref": "https://example.com/b.json"}}}
+//	"https://example.com/b.json": {"$id": "https://example.com/b.json"}
+func Unbundle(root *Schema) (map[string]*Schema, error) {
+	ids, err := ComputeIdentifiers(*root)
+	if err != nil {
+		return nil, fmt.Errorf("schema.Unbundle: %w", err)
+	}
+
+	type document struct {
+		path   []string
+		id     string
+		schema *Schema
+	}
+
+	c := Copy(*root)
+	docs := []*document{{id: root.ID, schema: &c}}
+	for ptr, identifiers := range ids {
+		if identifiers.BaseURI+"#" != identifiers.CanonResourcePointerURI {
+			continue
+		}
+
+		path := getUnescapedPath(ptr)
+		s, _, err := resolveRef(ResolveConfig{ignoreRefs: true}, root, path, 0)
+		if err != nil {
+			return nil, fmt.Errorf("schema.Unbundle: failed to locate embedded resource %q: %w", identifiers.BaseURI, err)
+		}
+
+		c := Copy(*s)
+		c.ID = identifiers.BaseURI
+		docs = append(docs, &document{path: path, id: c.ID, schema: &c})
+	}
+
+	// rewrite returns ref, resolved against base, relative to the innermost
+	// document containing the referenced schema.
+	rewrite := func(base *url.URL, ref string) string {
+		u, err := url.Parse(ref)
+		if err != nil {
+			return ref
+		}
+
+		u = base.ResolveReference(u)
+		if u.Fragment != "" && u.Fragment[0] != '/' {
+			return ref
+		}
+
+		fragment := u.Fragment
+		u.Fragment = ""
+
+		i := slices.IndexFunc(docs, func(d *document) bool {
+			return d.id == u.String()
+		})
+		if i < 0 {
+			return ref
+		}
+
+		target, path := docs[i], append(slices.Clip(docs[i].path), getUnescapedPath(fragment)...)
+		for _, d := range docs {
+			if len(d.path) > len(target.path) && len(d.path) <= len(path) && slices.Equal(d.path, path[:len(d.path)]) {
+				target = d
+			}
+		}
+		if target == docs[i] {
+			return ref
+		}
+
+		var sb strings.Builder
+		sb.WriteString(target.id)
+		for _, token := range path[len(target.path):] {
+			if sb.Len() == len(target.id) {
+				sb.WriteByte('#')
+			}
+			sb.WriteString("/" + escapeToken(token))
+		}
+		return sb.String()
+	}
+
+	m := make(map[string]*Schema, len(docs))
+	for _, d := range docs {
+		base, err := url.Parse(d.id)
+		if err != nil {
+			return nil, fmt.Errorf("schema.Unbundle: invalid $id %q: %w", d.id, err)
+		}
+
+		err = Walk(d.schema, func(ptr string, s *Schema) error {
+			if ptr != "/" && s.ID != "" {
+				id, err := url.Parse(s.ID)
+				if err != nil {
+					return fmt.Errorf("invalid $id %q: %w", s.ID, err)
+				}
+				*s = Schema{Ref: base.ResolveReference(id).String()}
+				return Skip
+			}
+
+			if s.Ref != "" {
+				s.Ref = rewrite(base, s.Ref)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("schema.Unbundle: %w", err)
+		}
+		m[d.id] = d.schema
+	}
+	return m, nil
+}
@@ -0,0 +1,100 @@
+package jsonschema_test
+
+import (
+	"encoding/json"
+	. "jsonschema"
+	"reflect"
+	"testing"
+)
+
+func TestUnbundle(t *testing.T) {
+	const bundle = `{
+    "$id": "https://example.com/person.json",
+    "properties": {
+        "name": { "$ref": "#/$defs/name" },
+        "address": { "$ref": "#/$defs/address" },
+        "street": { "$ref": "#/$defs/address/properties/street" },
+        "geo": { "$ref": "#/$defs/address/$defs/geo/properties/lat" },
+        "country": { "$id": "country.json", "type": "string" }
+    },
+    "$defs": {
+        "name": { "type": "string" },
+        "address": {
+            "$id": "address.json",
+            "properties": {
+                "street": { "type": "string" },
+                "name": { "$ref": "person.json#/$defs/name" },
+                "location": { "$ref": "#/$defs/geo" }
+            },
+            "$defs": {
+                "geo": {
+                    "$id": "geo/point.json",
+                    "properties": { "lat": { "type": "number" } }
+                }
+            }
+        }
+    }
+}`
+
+	root := &Schema{}
+	if err := json.Unmarshal([]byte(bundle), root); err != nil {
+		t.Logf("invalid schema: %s", err)
+		t.FailNow()
+	}
+	original := Copy(*root)
+
+	docs, err := Unbundle(root)
+	if err != nil {
+		t.Logf("unexpected error: %s", err)
+		t.FailNow()
+	}
+
+	expected := map[string]*Schema{
+		"https://example.com/person.json": {
+			ID: "https://example.com/person.json",
+			Properties: map[string]Schema{
+				"name":    {Ref: "#/$defs/name"},
+				"address": {Ref: "https://example.com/address.json"},
+				"street":  {Ref: "https://example.com/address.json#/properties/street"},
+				"geo":     {Ref: "https://example.com/geo/point.json#/properties/lat"},
+				"country": {Ref: "https://example.com/country.json"},
+			},
+			Defs: map[string]Schema{
+				"name":    {Type: TypeSet{TypeString}},
+				"address": {Ref: "https://example.com/address.json"},
+			},
+		},
+		"https://example.com/address.json": {
+			ID: "https://example.com/address.json",
+			Properties: map[string]Schema{
+				"street":   {Type: TypeSet{TypeString}},
+				"name":     {Ref: "person.json#/$defs/name"},
+				"location": {Ref: "https://example.com/geo/point.json"},
+			},
+			Defs: map[string]Schema{
+				"geo": {Ref: "https://example.com/geo/point.json"},
+			},
+		},
+		"https://example.com/geo/point.json": {
+			ID: "https://example.com/geo/point.json",
+			Properties: map[string]Schema{
+				"lat": {Type: TypeSet{TypeNumber}},
+			},
+		},
+		"https://example.com/country.json": {
+			ID:   "https://example.com/country.json",
+			Type: TypeSet{TypeString},
+		},
+	}
+
+	if !reflect.DeepEqual(docs, expected) {
+		for id, doc := range docs {
+			t.Logf("%s: %s", id, doc)
+		}
+		t.Errorf("unexpected documents")
+	}
+
+	if !reflect.DeepEqual(*root, original) {
+		t.Errorf("root was modified")
+	}
+}
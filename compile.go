@@ -0,0 +1,202 @@
+package jsonschema
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+)
+
+// CompiledSchema is a schema prepared for repeated validation. All references
+// of the schema are resolved and all patterns are compiled once by Compile.
+type CompiledSchema struct {
+	root     *Schema
+	uri      *url.URL
+	config   ValidateConfig
+	refs     map[string]compiledRef
+	anchors  map[string]map[string]*Schema // by resource URI
+	patterns map[string]*regexp.Regexp
+
+	// lax defers the errors of unresolvable references to the validation and
+	// leaves invalid patterns to the validator, see Schema.Validate.
+	lax bool
+}
+
+// compiledRef is the schema a $ref or $dynamicRef is resolved to, located in
+// resource identified by uri. If the reference cannot be resolved, err is set.
+type compiledRef struct {
+	schema   *Schema
+	resource *Schema
+	uri      *url.URL
+	err      error
+}
+
+// Compile prepares schema for the validation of many instances. Every $ref and
+// $dynamicRef is resolved, external references using loader, and every pattern
+// and every key of patternProperties is compiled. An error is returned if a
+// reference cannot be resolved or a pattern is invalid.
+//
+// The schema must not be modified after compilation.
+func Compile(ctx context.Context, schema *Schema, loader Loader) (*CompiledSchema, error) {
+	return CompileWithConfig(ctx, ValidateConfig{}, schema, loader)
+}
+
+// CompileWithConfig is like Compile, but allows to configure the validation of
+// the compiled schema.
+func CompileWithConfig(ctx context.Context, config ValidateConfig, schema *Schema, loader Loader) (*CompiledSchema, error) {
+	c, err := compileSchema(ctx, config, schema, loader, false)
+	if err != nil {
+		return nil, fmt.Errorf("schema.Compile: %w", err)
+	}
+	return c, nil
+}
+
+func compileSchema(ctx context.Context, config ValidateConfig, schema *Schema, loader Loader, lax bool) (*CompiledSchema, error) {
+	rc := ResolveConfig{Context: ctx, Loader: loader, keepRefs: true}
+	applyDefaults(&rc, schema)
+	if rc.resourceURI == nil {
+		// The $id of the schema is not a valid URI.
+		rc.resourceURI = &url.URL{}
+	}
+
+	c := &CompiledSchema{
+		root:     schema,
+		uri:      rc.resourceURI,
+		config:   config,
+		refs:     make(map[string]compiledRef),
+		anchors:  make(map[string]map[string]*Schema),
+		patterns: make(map[string]*regexp.Regexp),
+		lax:      lax,
+	}
+	if err := c.compile(rc, schema, ""); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// compile compiles s and all subschemas, ptr is the location of s within the
+// resource described by config.
+func (c *CompiledSchema) compile(config ResolveConfig, s *Schema, ptr string) error {
+	// The $id of the current resource is already applied to config.
+	if s.ID != "" && s != config.resource {
+		uri, _ := url.Parse(s.ID)
+		config.resource = s
+		config.resourceURI = config.resourceURI.ResolveReference(uri)
+		ptr = ""
+	}
+	if _, ok := c.anchors[config.resourceURI.String()]; !ok {
+		c.anchors[config.resourceURI.String()] = dynamicAnchors(config.resource)
+	}
+
+	patterns := make([]string, 0, len(s.PatternProperties)+1)
+	if s.Pattern != nil {
+		patterns = append(patterns, *s.Pattern)
+	}
+	for _, p := range sortedKeys(s.PatternProperties) {
+		patterns = append(patterns, p)
+	}
+	for _, p := range patterns {
+		if _, ok := c.patterns[p]; ok {
+			continue
+		}
+		re, err := regexp.Compile(p)
+		if err != nil {
+			if c.lax {
+				continue
+			}
+			return fmt.Errorf("invalid pattern %q at %q: %w", p, ptr, err)
+		}
+		c.patterns[p] = re
+	}
+
+	for _, child := range Children(s) {
+		if err := c.compile(config, child.Schema, ptr+"/"+child.Segment()); err != nil {
+			return err
+		}
+	}
+
+	for _, r := range []struct {
+		keyword, ref string
+	}{
+		{"$ref", s.Ref},
+		{"$dynamicRef", s.DynamicRef},
+	} {
+		if r.ref == "" {
+			continue
+		}
+		if err := c.compileRef(config, s, r.keyword, r.ref, ptr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// compileRef resolves the reference ref of the keyword of s. The schema of a
+// $dynamicRef is the schema it resolves to statically, the dynamic scope is only
+// known during the validation.
+func (c *CompiledSchema) compileRef(config ResolveConfig, s *Schema, keyword, ref, ptr string) error {
+	u, err := url.Parse(ref)
+	if err != nil {
+		return fmt.Errorf("invalid reference %q at %q: %w", ref, ptr, err)
+	}
+	key := config.resourceURI.ResolveReference(u).String()
+	if _, ok := c.refs[key]; ok {
+		return nil
+	}
+
+	var (
+		target *Schema
+		tc     ResolveConfig
+	)
+	if keyword == "$dynamicRef" {
+		config.dynamicScope = nil
+		target, tc, err = resolveDynamicRef(config, s)
+	} else {
+		target, tc, err = resolveReference(config, ref, s)
+	}
+	if err != nil {
+		err = fmt.Errorf("failed to resolve {%q: %q} at %q: %w", keyword, ref, ptr, err)
+		if !c.lax {
+			return err
+		}
+		c.refs[key] = compiledRef{err: err}
+		return nil
+	}
+
+	c.refs[key] = compiledRef{schema: target, resource: tc.resource, uri: tc.resourceURI}
+	return c.compile(tc, target, "")
+}
+
+// dynamicAnchors returns the schemas of the resource by their $dynamicAnchor,
+// embedded resources are not searched.
+func dynamicAnchors(resource *Schema) map[string]*Schema {
+	anchors := make(map[string]*Schema)
+	_ = Walk(resource, func(ptr string, s *Schema) error {
+		if ptr != "/" && s.ID != "" {
+			return Skip
+		}
+		if _, ok := anchors[s.DynamicAnchor]; s.DynamicAnchor != "" && !ok {
+			anchors[s.DynamicAnchor] = s
+		}
+		return nil
+	})
+	return anchors
+}
+
+// Validate validates the instance like Schema.Validate, using the compiled
+// references and patterns. It is safe for concurrent use.
+func (c *CompiledSchema) Validate(instance any) *ValidationResult {
+	v := c.validator()
+	errs := v.validate(c.root, instance, "", "")
+	return &ValidationResult{Valid: len(errs) == 0, Errors: errs}
+}
+
+func (c *CompiledSchema) validator() *validator {
+	return &validator{
+		config:   c.config,
+		patterns: c.patterns,
+		refs:     c.refs,
+		anchors:  c.anchors,
+		scope:    []scopeEntry{{resource: c.root, uri: c.uri}},
+	}
+}
@@ -0,0 +1,200 @@
+package jsonschema_test
+
+import (
+	"context"
+	"encoding/json"
+	. "jsonschema"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestCompile(t *testing.T) {
+	schema := &Schema{
+		ID: "https://example.com/order",
+		Properties: map[string]Schema{
+			"id":       {Ref: "#/$defs/id"},
+			"customer": {Ref: "customer"},
+		},
+		Defs: map[string]Schema{
+			"id": {Pattern: ptr("^[0-9]{4}$")},
+		},
+	}
+
+	var loaded []string
+	loader := LoaderFunc(func(_ context.Context, uri *url.URL) (*Schema, error) {
+		loaded = append(loaded, uri.String())
+		return &Schema{ID: uri.String(), PatternProperties: map[string]Schema{"^x-": True}}, nil
+	})
+
+	c, err := Compile(context.Background(), schema, loader)
+	if err != nil {
+		t.Logf("unexpected error: %s", err)
+		t.FailNow()
+	}
+	if len(loaded) != 1 || loaded[0] != "https://example.com/customer" {
+		t.Errorf("expected the customer to be loaded once, have %v", loaded)
+	}
+
+	tests := []struct {
+		instance any
+		valid    bool
+	}{
+		{instance: "1234", valid: true},
+		{instance: json.Number("12"), valid: true},
+		{instance: map[string]any{"id": "1234"}, valid: true},
+	}
+	for i, test := range tests {
+		if r := c.Validate(test.instance); r.Valid != test.valid {
+			t.Errorf("test #%d: have valid=%t, need valid=%t: %v", i, r.Valid, test.valid, r.Errors)
+		}
+	}
+
+	pattern := &Schema{If: &Schema{}, Then: &Schema{Pattern: ptr("^a")}}
+	if c, err = Compile(context.Background(), pattern, nil); err != nil {
+		t.Logf("unexpected error: %s", err)
+		t.FailNow()
+	}
+	if r := c.Validate("b"); r.Valid || len(r.Errors) != 1 || r.Errors[0].KeywordLocation != "/then/pattern" {
+		t.Errorf("expected pattern error, have %v", r.Errors)
+	}
+}
+
+func TestCompile_Refs(t *testing.T) {
+	const tree = `{
+		"$id": "https://example.com/tree.json",
+		"$dynamicAnchor": "node",
+		"type": "object",
+		"properties": {
+			"data": true,
+			"children": {"type": "array", "items": {"$dynamicRef": "#node"}}
+		}
+	}`
+
+	docs := map[string]string{
+		"https://example.com/tree.json": tree,
+		"https://example.com/strict-tree.json": `{
+			"$id": "https://example.com/strict-tree.json",
+			"$dynamicAnchor": "node",
+			"$ref": "tree.json",
+			"properties": {"data": {"type": "string"}}
+		}`,
+		"https://example.com/name.json": `{
+			"$id": "https://example.com/name.json",
+			"$ref": "#/$defs/name",
+			"$defs": {"name": {"type": "string", "minLength": 1}}
+		}`,
+	}
+	loader := LoaderFunc(func(_ context.Context, uri *url.URL) (*Schema, error) {
+		u := *uri
+		u.Fragment = ""
+		doc, ok := docs[u.String()]
+		if !ok {
+			return nil, UnsupportedURI
+		}
+		s := &Schema{}
+		if err := json.Unmarshal([]byte(doc), s); err != nil {
+			return nil, err
+		}
+		return s, nil
+	})
+
+	tests := []struct {
+		schema   string
+		instance string
+		valid    bool
+	}{
+		// The instance only fails a constraint behind a $ref.
+		{schema: `{"$ref": "#/$defs/a", "$defs": {"a": {"type": "string"}}}`, instance: `5`, valid: false},
+		{schema: `{"$ref": "#/$defs/a", "$defs": {"a": {"type": "string"}}}`, instance: `"5"`, valid: true},
+		// A reference within a referenced document is resolved against it.
+		{schema: `{"properties": {"name": {"$ref": "https://example.com/name.json"}}}`, instance: `{"name": ""}`, valid: false},
+		{schema: `{"properties": {"name": {"$ref": "https://example.com/name.json"}}}`, instance: `{"name": "a"}`, valid: true},
+		// A recursive reference is followed as deep as the instance.
+		{schema: `{"properties": {"next": {"$ref": "#"}}, "required": ["v"]}`, instance: `{"v": 1, "next": {"v": 2, "next": {}}}`, valid: false},
+		{schema: `{"properties": {"next": {"$ref": "#"}}, "required": ["v"]}`, instance: `{"v": 1, "next": {"v": 2}}`, valid: true},
+		// A reference cycle not consuming the instance terminates.
+		{schema: `{"$ref": "#/$defs/a", "$defs": {"a": {"$ref": "#/$defs/b"}, "b": {"$ref": "#/$defs/a"}}}`, instance: `1`, valid: true},
+		// $dynamicRef is resolved to the outermost resource of the dynamic scope.
+		{schema: tree, instance: `{"children": [{"data": 1}]}`, valid: true},
+		{schema: `{"$ref": "https://example.com/strict-tree.json"}`, instance: `{"children": [{"data": "a"}]}`, valid: true},
+		{schema: `{"$ref": "https://example.com/strict-tree.json"}`, instance: `{"children": [{"data": 1}]}`, valid: false},
+	}
+
+	for i, test := range tests {
+		schema := &Schema{}
+		if err := json.Unmarshal([]byte(test.schema), schema); err != nil {
+			t.Errorf("test #%d: invalid schema: %s", i, err)
+			continue
+		}
+		c, err := Compile(context.Background(), schema, loader)
+		if err != nil {
+			t.Errorf("test #%d: unexpected error: %s", i, err)
+			continue
+		}
+
+		var instance any
+		_ = json.Unmarshal([]byte(test.instance), &instance)
+		if r := c.Validate(instance); r.Valid != test.valid {
+			t.Errorf("test #%d: have valid=%t, need valid=%t: %v", i, r.Valid, test.valid, r.Errors)
+		}
+	}
+}
+
+func TestCompileWithConfig(t *testing.T) {
+	schema := &Schema{Format: ptr("unknown")}
+
+	c, err := CompileWithConfig(context.Background(), ValidateConfig{StrictFormats: true}, schema, nil)
+	if err != nil {
+		t.Logf("unexpected error: %s", err)
+		t.FailNow()
+	}
+	if r := c.Validate("a"); r.Valid {
+		t.Errorf("expected the unknown format to fail")
+	}
+
+	if c, err = Compile(context.Background(), schema, nil); err != nil {
+		t.Logf("unexpected error: %s", err)
+		t.FailNow()
+	}
+	if r := c.Validate("a"); !r.Valid {
+		t.Errorf("unexpected errors %v", r.Errors)
+	}
+}
+
+func TestCompile_Invalid(t *testing.T) {
+	tests := map[string]struct {
+		schema *Schema
+		err    string
+	}{
+		"pattern": {
+			schema: &Schema{Properties: map[string]Schema{"a": {Pattern: ptr("[a-")}}},
+			err:    `invalid pattern "[a-" at "/properties/a"`,
+		},
+		"pattern property": {
+			schema: &Schema{PatternProperties: map[string]Schema{"(": True}},
+			err:    `invalid pattern "(" at ""`,
+		},
+		"pattern in referenced schema": {
+			schema: &Schema{Ref: "#/$defs/a", Defs: map[string]Schema{"a": {Pattern: ptr("*")}}},
+			err:    `invalid pattern "*"`,
+		},
+		"unresolvable reference": {
+			schema: &Schema{Items: &Schema{Ref: "#/$defs/missing"}},
+			err:    `failed to resolve {"$ref": "#/$defs/missing"} at "/items"`,
+		},
+		"external reference without loader": {
+			schema: &Schema{Ref: "https://example.com/missing"},
+			err:    `failed to resolve {"$ref": "https://example.com/missing"}`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Compile(context.Background(), test.schema, nil)
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("have %v, need error containing %q", err, test.err)
+			}
+		})
+	}
+}
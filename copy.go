@@ -67,9 +67,28 @@ func Copy(src Schema) Schema {
 		ReadOnly:              copyPtr(src.ReadOnly),
 		WriteOnly:             copyPtr(src.WriteOnly),
 		Examples:              copyAny(src.Examples),
+		Draft07Tuple:          src.Draft07Tuple,
 	}
 }
 
+// CopyShallow creates a shallow copy of a schema. Only the fields of the schema
+// itself are copied, all subschemas, slices, maps and pointers are shared with
+// src. Replacing a field of the copy does not affect src, but modifying a shared
+// value does, e.g. adding a property or changing a subschema. Use Copy unless the
+// shared values are only ever replaced as a whole.
+func CopyShallow(src Schema) Schema {
+	return src
+}
+
+// Clone returns a deep copy of s, or nil if s is nil.
+func (s *Schema) Clone() *Schema {
+	if s == nil {
+		return nil
+	}
+	c := Copy(*s)
+	return &c
+}
+
 // copyAny copies any data by marshalling and unmarshalling it. This is a somewhat
 // costly operation, but the only way to reliably copy unknown types without massive
 // amounts of reflection magic. This method is only to be used for the following
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	. "jsonschema"
 	"maps"
+	"reflect"
 	"slices"
 	"testing"
 )
@@ -126,3 +127,47 @@ func TestCopy(t *testing.T) {
 		t.FailNow()
 	}
 }
+
+func TestSchema_Clone(t *testing.T) {
+	if c := (*Schema)(nil).Clone(); c != nil {
+		t.Logf("expected nil, got %s", c)
+		t.FailNow()
+	}
+
+	s := &Schema{Items: &Schema{Not: &Schema{}}, Required: []string{"foo"}}
+	c := s.Clone()
+
+	if c == s || c.Items == s.Items || c.Items.Not == s.Items.Not {
+		t.Logf("clone shares memory with the original schema")
+		t.FailNow()
+	}
+
+	if !reflect.DeepEqual(s, c) {
+		t.Logf("have: %s", c)
+		t.Logf("need: %s", s)
+		t.FailNow()
+	}
+}
+
+func TestCopyShallow(t *testing.T) {
+	s := Schema{
+		Title:      "foo",
+		Items:      &Schema{Type: TypeSet{TypeString}},
+		Properties: map[string]Schema{"a": True},
+		Required:   []string{"a"},
+	}
+	c := CopyShallow(s)
+
+	if !reflect.DeepEqual(s, c) || c.Items != s.Items {
+		t.Logf("expected a copy sharing the subschemas, got %s", &c)
+		t.FailNow()
+	}
+
+	c.Title = "bar"
+	c.Items = &Schema{Type: TypeSet{TypeNumber}}
+	c.Required = nil
+	if s.Title != "foo" || !reflect.DeepEqual(s.Items.Type, TypeSet{TypeString}) || len(s.Required) != 1 {
+		t.Logf("replacing a field of the copy modified the original schema: %s", &s)
+		t.FailNow()
+	}
+}
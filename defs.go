@@ -0,0 +1,108 @@
+package jsonschema
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// MergeDefs adds the $defs of every schema in from to the $defs of into, e.g. to
+// assemble the schemas generated by FromGoType for several types into a single
+// document. A definition that already exists is only accepted if both schemas are
+// equal, into is not modified if any definition conflicts.
+//
+// The roots of the source schemas are not merged. A schema generated for a named
+// type is a reference to its definition, which is valid in into once merged.
+func MergeDefs(into *Schema, from ...*Schema) error {
+	defs := make(map[string]Schema)
+	for _, src := range from {
+		for name, def := range src.Defs {
+			existing, ok := into.Defs[name]
+			if !ok {
+				existing, ok = defs[name]
+			}
+			if ok && !reflect.DeepEqual(existing, def) {
+				return fmt.Errorf("schema.MergeDefs: conflicting definitions of %q", name)
+			}
+			defs[name] = def
+		}
+	}
+
+	if len(defs) != 0 && into.Defs == nil {
+		into.Defs = make(map[string]Schema, len(defs))
+	}
+	for name, def := range defs {
+		if _, ok := into.Defs[name]; !ok {
+			into.Defs[name] = Copy(def)
+		}
+	}
+	return nil
+}
+
+// RenameDef renames the definition old of root to new and rewrites every reference
+// to it or into it, e.g. "#/$defs/old/items" becomes "#/$defs/new/items". Both
+// fragment-only references of the root resource and references using the $id of
+// root are rewritten. References within embedded schema resources are resolved
+// against their own $id and only rewritten if they use the $id of root.
+//
+// An error is returned if old does not exist or new already exists.
+func RenameDef(root *Schema, old, new string) error {
+	def, ok := root.Defs[old]
+	if !ok {
+		return fmt.Errorf("schema.RenameDef: unknown definition %q", old)
+	}
+	if old == new {
+		return nil
+	}
+	if _, ok = root.Defs[new]; ok {
+		return fmt.Errorf("schema.RenameDef: definition %q already exists", new)
+	}
+
+	delete(root.Defs, old)
+	root.Defs[new] = def
+
+	// rename returns ref pointing to the renamed definition, if ref points to old.
+	rename := func(ref string) string {
+		base, fragment, ok := strings.Cut(ref, "#")
+		if !ok || (base != "" && base != root.ID) {
+			return ref
+		}
+		if f, err := url.PathUnescape(fragment); err == nil {
+			fragment = f
+		}
+
+		path := getUnescapedPath(fragment)
+		if !strings.HasPrefix(fragment, "/") || len(path) < 2 || path[0] != "$defs" || path[1] != old {
+			return ref
+		}
+
+		path[1] = new
+		for i := range path {
+			path[i] = escapeToken(path[i])
+		}
+		return base + "#/" + strings.Join(path, "/")
+	}
+
+	// The embedded resources are visited before their subschemas.
+	var resources []string
+	return Walk(root, func(ptr string, s *Schema) error {
+		if ptr != "/" && s.ID != "" {
+			resources = append(resources, ptr)
+		}
+		if s.Ref == "" {
+			return nil
+		}
+
+		// A fragment-only reference within an embedded resource is relative to
+		// the resource, not to root.
+		embedded := slices.ContainsFunc(resources, func(r string) bool {
+			return ptr == r || strings.HasPrefix(ptr, r+"/")
+		})
+		if !embedded || !strings.HasPrefix(s.Ref, "#") {
+			s.Ref = rename(s.Ref)
+		}
+		return nil
+	})
+}
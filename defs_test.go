@@ -0,0 +1,135 @@
+package jsonschema_test
+
+import (
+	"encoding/json"
+	. "jsonschema"
+	"reflect"
+	"testing"
+)
+
+func TestMergeDefs(t *testing.T) {
+	type Address struct {
+		Street string `json:"street"`
+	}
+	type Person struct {
+		Name    string  `json:"name"`
+		Address Address `json:"address"`
+	}
+	type Company struct {
+		Address Address `json:"address"`
+	}
+
+	person, _ := FromGoType(reflect.TypeOf(Person{}))
+	company, _ := FromGoType(reflect.TypeOf(Company{}))
+
+	into := &Schema{
+		OneOf: []Schema{*person, *company},
+	}
+	into.OneOf[0].Defs, into.OneOf[1].Defs = nil, nil
+
+	if err := MergeDefs(into, person, company); err != nil {
+		t.Logf("unexpected error: %s", err)
+		t.FailNow()
+	}
+
+	expected := map[string]Schema{
+		"Address": person.Defs["Address"],
+		"Person":  person.Defs["Person"],
+		"Company": company.Defs["Company"],
+	}
+	if !reflect.DeepEqual(into.Defs, expected) {
+		t.Errorf("\nhave %v\nneed %v", into.Defs, expected)
+	}
+
+	into.Defs["Address"].Properties["street"] = True
+	if street := person.Defs["Address"].Properties["street"]; street.IsTrue() {
+		t.Errorf("source definition was modified")
+	}
+
+	t.Run("conflict", func(t *testing.T) {
+		into := &Schema{Defs: map[string]Schema{"Person": True}}
+		other := &Schema{Defs: map[string]Schema{"Other": True}}
+
+		err := MergeDefs(into, other, person)
+		if err == nil || err.Error() != `schema.MergeDefs: conflicting definitions of "Person"` {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if _, ok := into.Defs["Other"]; ok {
+			t.Errorf("definitions were merged despite a conflict")
+		}
+
+		err = MergeDefs(&Schema{}, person, &Schema{Defs: map[string]Schema{"Address": False}})
+		if err == nil {
+			t.Errorf("expected an error for conflicting sources")
+		}
+	})
+}
+
+func TestRenameDef(t *testing.T) {
+	schema := func() *Schema {
+		s := &Schema{}
+		_ = json.Unmarshal([]byte(`{
+			"$id": "https://example.com/root",
+			"properties": {
+				"a": {"$ref": "#/$defs/List%5Bint%5D"},
+				"b": {"$ref": "#/$defs/List[int]/items"},
+				"c": {"$ref": "https://example.com/root#/$defs/List[int]"},
+				"d": {"$ref": "#/$defs/List[int]x"},
+				"e": {"$ref": "other#/$defs/List[int]"}
+			},
+			"$defs": {
+				"List[int]": {"items": {"$ref": "#/$defs/List[int]"}},
+				"embedded": {
+					"$id": "embedded",
+					"$defs": {"List[int]": {}},
+					"items": {"$ref": "#/$defs/List[int]"},
+					"not": {"$ref": "https://example.com/root#/$defs/List[int]"}
+				}
+			}
+		}`), s)
+		return s
+	}
+
+	s := schema()
+	if err := RenameDef(s, "List[int]", "List/int"); err != nil {
+		t.Logf("unexpected error: %s", err)
+		t.FailNow()
+	}
+
+	if _, ok := s.Defs["List[int]"]; ok {
+		t.Errorf("expected old definition to be removed")
+	}
+	if def := s.Defs["List/int"]; def.Items == nil || def.Items.Ref != "#/$defs/List~1int" {
+		t.Errorf("expected renamed definition, have %s", &def)
+	}
+
+	expected := map[string]string{
+		"a": "#/$defs/List~1int",
+		"b": "#/$defs/List~1int/items",
+		"c": "https://example.com/root#/$defs/List~1int",
+		"d": "#/$defs/List[int]x",
+		"e": "other#/$defs/List[int]",
+	}
+	for name, ref := range expected {
+		if have := s.Properties[name].Ref; have != ref {
+			t.Errorf("%s: have %q, need %q", name, have, ref)
+		}
+	}
+
+	embedded := s.Defs["embedded"]
+	if ref := embedded.Items.Ref; ref != "#/$defs/List[int]" {
+		t.Errorf("expected reference within embedded resource to be kept, have %q", ref)
+	}
+	if ref := embedded.Not.Ref; ref != "https://example.com/root#/$defs/List~1int" {
+		t.Errorf("expected absolute reference to be rewritten, have %q", ref)
+	}
+
+	for name, test := range map[string][2]string{
+		"unknown": {"missing", "x"},
+		"exists":  {"List[int]", "embedded"},
+	} {
+		if err := RenameDef(schema(), test[0], test[1]); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
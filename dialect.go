@@ -0,0 +1,33 @@
+package jsonschema
+
+import "strings"
+
+// Dialect is the URI of a meta-schema identifying a JSON Schema dialect, as used
+// by the $schema keyword.
+type Dialect string
+
+const (
+	Draft2020Schema Dialect = "https://json-schema.org/draft/2020-12/schema"
+	Draft2019Schema Dialect = "https://json-schema.org/draft/2019-09/schema"
+	Draft07Schema   Dialect = "http://json-schema.org/draft-07/schema#"
+)
+
+var dialects = []Dialect{Draft2020Schema, Draft2019Schema, Draft07Schema}
+
+// DialectOf returns the dialect declared by the $schema keyword of s. The second
+// return value is false if $schema is not set or is not one of the known dialects.
+// An empty fragment is ignored, e.g. "https://json-schema.org/draft/2020-12/schema#"
+// denotes Draft2020Schema.
+func DialectOf(s *Schema) (Dialect, bool) {
+	uri := strings.TrimSuffix(s.Schema, "#")
+	if uri == "" {
+		return "", false
+	}
+
+	for _, d := range dialects {
+		if strings.TrimSuffix(string(d), "#") == uri {
+			return d, true
+		}
+	}
+	return "", false
+}
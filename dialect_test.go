@@ -0,0 +1,30 @@
+package jsonschema_test
+
+import (
+	. "jsonschema"
+	"testing"
+)
+
+func TestDialectOf(t *testing.T) {
+	tests := []struct {
+		schema string
+		want   Dialect
+		ok     bool
+	}{
+		{schema: "https://json-schema.org/draft/2020-12/schema", want: Draft2020Schema, ok: true},
+		{schema: "https://json-schema.org/draft/2020-12/schema#", want: Draft2020Schema, ok: true},
+		{schema: "https://json-schema.org/draft/2019-09/schema", want: Draft2019Schema, ok: true},
+		{schema: "http://json-schema.org/draft-07/schema#", want: Draft07Schema, ok: true},
+		{schema: "http://json-schema.org/draft-07/schema", want: Draft07Schema, ok: true},
+		{schema: "https://json-schema.org/draft/2020-12/schema#/x"},
+		{schema: "https://example.com/custom-dialect"},
+		{schema: ""},
+	}
+
+	for _, test := range tests {
+		d, ok := DialectOf(&Schema{Schema: test.schema})
+		if d != test.want || ok != test.ok {
+			t.Errorf("%q:\nhave %q, %t\nneed %q, %t", test.schema, d, ok, test.want, test.ok)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"math/big"
+	"slices"
+)
+
+// Disjoint reports whether no instance is valid against both a and b, e.g. the
+// branches of a oneOf should be disjoint. The check is not complete, false is
+// returned if it cannot be determined whether the schemas are disjoint. The
+// following is considered:
+//
+//   - false schemas
+//   - the types of both schemas, inferred from const and enum if not declared
+//   - the const and enum values, with respect to the type and numeric range of
+//     the other schema
+//   - the numeric ranges of schemas only allowing numbers
+//
+// References and applicators like allOf are ignored.
+//
+//	{"type":"string"} and {"type":"integer"}            // disjoint
+//	{"enum":[1,2]} and {"minimum":3}                    // disjoint
+//	{"type":"integer","maximum":0} and {"minimum":0}    // not disjoint
+func Disjoint(a, b *Schema) bool {
+	if a.IsFalse() || b.IsFalse() {
+		return true
+	}
+
+	ta, tb := InferTypes(a), InferTypes(b)
+	if len(ta) > 0 && len(tb) > 0 && !slices.ContainsFunc(ta, func(t Type) bool { return typeAllowed(tb, t) }) {
+		return true
+	}
+
+	for _, s := range [][2]*Schema{{a, b}, {b, a}} {
+		if values, ok := constValues(s[0]); ok && !slices.ContainsFunc(values, func(v any) bool { return allows(s[1], v) }) {
+			return true
+		}
+	}
+
+	if !numeric(ta) || !numeric(tb) {
+		return false
+	}
+	lo, hi := maxBound(lowerBound(a), lowerBound(b), 1), maxBound(upperBound(a), upperBound(b), -1)
+	return !lo.le(hi)
+}
+
+// typeAllowed returns whether a value of type t is valid against the type set ts.
+func typeAllowed(ts TypeSet, t Type) bool {
+	return slices.Contains(ts, t) ||
+		(t == TypeInteger && slices.Contains(ts, TypeNumber)) ||
+		(t == TypeNumber && slices.Contains(ts, TypeInteger))
+}
+
+// numeric returns whether ts only contains numeric types.
+func numeric(ts TypeSet) bool {
+	return len(ts) > 0 && !slices.ContainsFunc(ts, func(t Type) bool {
+		return t != TypeNumber && t != TypeInteger
+	})
+}
+
+// constValues returns the values allowed by const or enum of s.
+func constValues(s *Schema) ([]any, bool) {
+	if s.Const != nil {
+		return []any{s.Const}, true
+	}
+	return s.Enum, s.Enum != nil
+}
+
+// allows returns false if v is known to be invalid against s.
+func allows(s *Schema, v any) bool {
+	t := InferValueType(v)
+	if len(s.Type) > 0 && !slices.Contains(s.Type, t) && !(t == TypeInteger && slices.Contains(s.Type, TypeNumber)) {
+		return false
+	}
+
+	if values, ok := constValues(s); ok {
+		key, err := canonicalJSON(v)
+		if err != nil {
+			return true
+		}
+		if !slices.ContainsFunc(values, func(other any) bool {
+			k, err := canonicalJSON(other)
+			return err != nil || k == key
+		}) {
+			return false
+		}
+	}
+
+	// The value is encoded to support numbers of any Go type.
+	b, err := json.Marshal(v)
+	if err != nil || len(b) == 0 || (b[0] != '-' && (b[0] < '0' || b[0] > '9')) {
+		return true
+	}
+	value := bound{n: new(big.Rat)}
+	if _, ok := value.n.SetString(string(b)); !ok {
+		return true
+	}
+	return lowerBound(s).le(value) && value.le(upperBound(s))
+}
+
+// bound is a lower or upper bound of a numeric range, n is nil if unbounded.
+type bound struct {
+	n         *big.Rat
+	exclusive bool
+}
+
+// le returns whether the range between the lower bound b and the upper bound
+// upper is not empty.
+func (b bound) le(upper bound) bool {
+	if b.n == nil || upper.n == nil {
+		return true
+	}
+	c := b.n.Cmp(upper.n)
+	return c < 0 || (c == 0 && !b.exclusive && !upper.exclusive)
+}
+
+func lowerBound(s *Schema) bound {
+	return maxBound(newBound(s.Minimum, false), newBound(s.ExclusiveMinimum, true), 1)
+}
+
+func upperBound(s *Schema) bound {
+	return maxBound(newBound(s.Maximum, false), newBound(s.ExclusiveMaximum, true), -1)
+}
+
+func newBound(n *json.Number, exclusive bool) bound {
+	if n == nil {
+		return bound{}
+	}
+	r, ok := new(big.Rat).SetString(string(*n))
+	if !ok {
+		return bound{}
+	}
+	return bound{n: r, exclusive: exclusive}
+}
+
+// maxBound returns the more restrictive bound, sign is 1 for lower bounds and -1
+// for upper bounds.
+func maxBound(a, b bound, sign int) bound {
+	switch {
+	case a.n == nil:
+		return b
+	case b.n == nil:
+		return a
+	}
+	if c := a.n.Cmp(b.n) * sign; c > 0 || (c == 0 && a.exclusive) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,59 @@
+package jsonschema_test
+
+import (
+	"encoding/json"
+	. "jsonschema"
+	"testing"
+)
+
+func TestDisjoint(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		disjoint bool
+	}{
+		{a: `{"type":"string"}`, b: `{"type":"integer"}`, disjoint: true},
+		{a: `{"type":["string","null"]}`, b: `{"type":"null"}`},
+		{a: `{"type":"number"}`, b: `{"type":"integer"}`},
+		{a: `{"type":"string"}`, b: `{}`},
+		{a: `{"type":"string"}`, b: `false`, disjoint: true},
+		{a: `{"const":"a"}`, b: `{"const":"b"}`, disjoint: true},
+		{a: `{"const":1}`, b: `{"const":1.0}`},
+		{a: `{"const":{"a":1,"b":2}}`, b: `{"enum":[{"b":2,"a":1}]}`},
+		{a: `{"enum":["a","b"]}`, b: `{"enum":["c",1]}`, disjoint: true},
+		{a: `{"enum":["a","b"]}`, b: `{"enum":["b","c"]}`},
+		{a: `{"const":1.5}`, b: `{"type":"integer"}`, disjoint: true},
+		{a: `{"const":2}`, b: `{"type":"number"}`},
+		{a: `{"enum":[1,2]}`, b: `{"minimum":3}`, disjoint: true},
+		{a: `{"enum":[1,2,"a"]}`, b: `{"minimum":3}`},
+		{a: `{"enum":[3]}`, b: `{"exclusiveMinimum":3}`, disjoint: true},
+		{a: `{"type":"integer","maximum":0}`, b: `{"type":"number","minimum":0}`},
+		{a: `{"type":"integer","exclusiveMaximum":0}`, b: `{"type":"number","minimum":0}`, disjoint: true},
+		{a: `{"type":"integer","maximum":10,"minimum":5}`, b: `{"type":"integer","maximum":4}`, disjoint: true},
+		{a: `{"type":"integer","maximum":10,"exclusiveMaximum":20}`, b: `{"type":"integer","minimum":10.5}`, disjoint: true},
+		{a: `{"maximum":0}`, b: `{"minimum":1}`},
+		{a: `{"type":"integer","maximum":0}`, b: `{"type":["integer","string"],"minimum":1}`},
+	}
+
+	for i, test := range tests {
+		var a, b Schema
+		if err := json.Unmarshal([]byte(test.a), &a); err != nil {
+			t.Fatal(err)
+		}
+		if err := json.Unmarshal([]byte(test.b), &b); err != nil {
+			t.Fatal(err)
+		}
+
+		if d := Disjoint(&a, &b); d != test.disjoint {
+			t.Errorf("test #%d: %s and %s: have %t, need %t", i, test.a, test.b, d, test.disjoint)
+		}
+		if d := Disjoint(&b, &a); d != test.disjoint {
+			t.Errorf("test #%d: %s and %s: have %t, need %t", i, test.b, test.a, d, test.disjoint)
+		}
+	}
+}
+
+func TestDisjoint_GoValues(t *testing.T) {
+	if !Disjoint(&Schema{Enum: []any{1, int8(2)}}, &Schema{Maximum: ptr(json.Number("0"))}) {
+		t.Errorf("expected enum of Go integers to be disjoint from negative numbers")
+	}
+}
@@ -0,0 +1,144 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"math/big"
+	"slices"
+)
+
+// Equal reports whether the schemas a and b are equal. Unlike reflect.DeepEqual,
+// the representation of a keyword is not compared where it has no meaning:
+//
+//   - nil and empty slices and maps are equal, except for enum, as an empty enum
+//     allows no value at all
+//   - numeric keywords are compared by their value, e.g. 1.0 equals 1
+//   - the values of enum, const, default and examples are compared by their JSON
+//     encoding, with numbers compared by their value and objects regardless of
+//     the order of their keys
+//   - type and required are compared as sets, regardless of order and
+//     duplicates
+//
+// Subschemas are compared recursively, so the schema {} equals True and
+// {"not":{}} equals False.
+func Equal(a, b Schema) bool {
+	return a.Schema == b.Schema &&
+		equalMap(a.Vocabulary, b.Vocabulary, func(x, y bool) bool { return x == y }) &&
+		a.ID == b.ID &&
+		a.Ref == b.Ref &&
+		a.Anchor == b.Anchor &&
+		a.DynamicRef == b.DynamicRef &&
+		a.DynamicAnchor == b.DynamicAnchor &&
+		equalMap(a.Defs, b.Defs, Equal) &&
+		a.Comment == b.Comment &&
+		equalSlice(a.AllOf, b.AllOf, Equal) &&
+		equalSlice(a.AnyOf, b.AnyOf, Equal) &&
+		equalSlice(a.OneOf, b.OneOf, Equal) &&
+		equalPtr(a.Not, b.Not, Equal) &&
+		equalPtr(a.If, b.If, Equal) &&
+		equalPtr(a.Then, b.Then, Equal) &&
+		equalPtr(a.Else, b.Else, Equal) &&
+		equalMap(a.DependentSchemas, b.DependentSchemas, Equal) &&
+		equalSlice(a.PrefixItems, b.PrefixItems, Equal) &&
+		equalPtr(a.Items, b.Items, Equal) &&
+		equalPtr(a.Contains, b.Contains, Equal) &&
+		equalMap(a.Properties, b.Properties, Equal) &&
+		equalMap(a.PatternProperties, b.PatternProperties, Equal) &&
+		equalPtr(a.AdditionalProperties, b.AdditionalProperties, Equal) &&
+		equalPtr(a.PropertyNames, b.PropertyNames, Equal) &&
+		equalSet(a.Type, b.Type) &&
+		(a.Enum == nil) == (b.Enum == nil) && equalSlice(a.Enum, b.Enum, equalValue) &&
+		equalValue(a.Const, b.Const) &&
+		equalPtr(a.MultipleOf, b.MultipleOf, equalNumber) &&
+		equalPtr(a.Maximum, b.Maximum, equalNumber) &&
+		equalPtr(a.ExclusiveMaximum, b.ExclusiveMaximum, equalNumber) &&
+		equalPtr(a.Minimum, b.Minimum, equalNumber) &&
+		equalPtr(a.ExclusiveMinimum, b.ExclusiveMinimum, equalNumber) &&
+		equalPtr(a.MaxLength, b.MaxLength, equalComparable[int]) &&
+		equalPtr(a.MinLength, b.MinLength, equalComparable[int]) &&
+		equalPtr(a.Pattern, b.Pattern, equalComparable[string]) &&
+		equalPtr(a.MaxItems, b.MaxItems, equalComparable[int]) &&
+		equalPtr(a.MinItems, b.MinItems, equalComparable[int]) &&
+		equalPtr(a.UniqueItems, b.UniqueItems, equalComparable[bool]) &&
+		equalPtr(a.MaxContains, b.MaxContains, equalComparable[int]) &&
+		equalPtr(a.MinContains, b.MinContains, equalComparable[int]) &&
+		equalPtr(a.MaxProperties, b.MaxProperties, equalComparable[int]) &&
+		equalPtr(a.MinProperties, b.MinProperties, equalComparable[int]) &&
+		equalSet(a.Required, b.Required) &&
+		equalMap(a.DependentRequired, b.DependentRequired, equalSet[string]) &&
+		equalPtr(a.UnevaluatedItems, b.UnevaluatedItems, Equal) &&
+		equalPtr(a.UnevaluatedProperties, b.UnevaluatedProperties, Equal) &&
+		equalPtr(a.Format, b.Format, equalComparable[string]) &&
+		equalPtr(a.ContentEncoding, b.ContentEncoding, equalComparable[string]) &&
+		equalPtr(a.ContentMediaType, b.ContentMediaType, equalComparable[string]) &&
+		equalPtr(a.ContentSchema, b.ContentSchema, Equal) &&
+		a.Title == b.Title &&
+		a.Description == b.Description &&
+		equalValue(a.Default, b.Default) &&
+		equalPtr(a.Deprecated, b.Deprecated, equalComparable[bool]) &&
+		equalPtr(a.ReadOnly, b.ReadOnly, equalComparable[bool]) &&
+		equalPtr(a.WriteOnly, b.WriteOnly, equalComparable[bool]) &&
+		equalSlice(a.Examples, b.Examples, equalValue)
+}
+
+func equalComparable[T comparable](a, b T) bool {
+	return a == b
+}
+
+func equalPtr[T any](a, b *T, eq func(T, T) bool) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return eq(*a, *b)
+}
+
+func equalSlice[T any](a, b []T, eq func(T, T) bool) bool {
+	return slices.EqualFunc(a, b, eq)
+}
+
+func equalMap[V any](a, b map[string]V, eq func(V, V) bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, va := range a {
+		vb, ok := b[k]
+		if !ok || !eq(va, vb) {
+			return false
+		}
+	}
+	return true
+}
+
+// equalSet reports whether a and b contain the same elements.
+func equalSet[T comparable](a, b []T) bool {
+	for _, v := range a {
+		if !slices.Contains(b, v) {
+			return false
+		}
+	}
+	for _, v := range b {
+		if !slices.Contains(a, v) {
+			return false
+		}
+	}
+	return true
+}
+
+func equalNumber(a, b json.Number) bool {
+	ra, okA := new(big.Rat).SetString(string(a))
+	rb, okB := new(big.Rat).SetString(string(b))
+	if !okA || !okB {
+		return a == b
+	}
+	return ra.Cmp(rb) == 0
+}
+
+// equalValue reports whether the JSON values a and b are equal. A value that
+// cannot be encoded equals no other value.
+func equalValue(a, b any) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	ka, errA := canonicalJSON(a)
+	kb, errB := canonicalJSON(b)
+	return errA == nil && errB == nil && ka == kb
+}
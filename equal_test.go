@@ -0,0 +1,87 @@
+package jsonschema_test
+
+import (
+	"encoding/json"
+	. "jsonschema"
+	"testing"
+)
+
+func TestEqual(t *testing.T) {
+	s := Schema{
+		ID:    "https://example.com/order",
+		Type:  TypeSet{TypeObject},
+		Title: "Order",
+		Defs: map[string]Schema{
+			"Line": {
+				Type:       TypeSet{TypeObject},
+				Properties: map[string]Schema{"qty": {Type: TypeSet{TypeInteger}, Minimum: ptr(json.Number("1"))}},
+			},
+		},
+		Properties: map[string]Schema{
+			"lines":  {Type: TypeSet{TypeArray}, Items: &Schema{Ref: "#/$defs/Line"}},
+			"status": {Enum: []any{"open", "closed", nil}},
+			"total":  {Type: TypeSet{TypeNumber}, MultipleOf: ptr(json.Number("0.01"))},
+		},
+		AdditionalProperties: &False,
+		Required:             []string{"lines", "status"},
+		DependentRequired:    map[string][]string{"total": {"lines"}},
+		Default:              map[string]any{"lines": []any{}, "status": "open"},
+		Examples:             []any{map[string]any{"lines": []any{}, "status": "closed"}},
+	}
+
+	if c := Copy(s); !Equal(s, c) {
+		t.Errorf("expected copy to be equal, have %s", &c)
+	}
+
+	equal := map[string][2]Schema{
+		"true":  {True, {}},
+		"false": {False, {Not: &Schema{}}},
+		"false subschema": {
+			{AdditionalProperties: &False},
+			{AdditionalProperties: &Schema{Not: &True}},
+		},
+		"empty slices and maps": {
+			{},
+			{AllOf: []Schema{}, Properties: map[string]Schema{}, Required: []string{}, Type: TypeSet{}},
+		},
+		"numbers":      {{Minimum: ptr(json.Number("1"))}, {Minimum: ptr(json.Number("1.0"))}},
+		"small number": {{MultipleOf: ptr(json.Number("0.01"))}, {MultipleOf: ptr(json.Number("1e-2"))}},
+		"values": {
+			{Const: map[string]any{"a": 1, "b": []any{2.5}}, Default: json.Number("1e1")},
+			{Const: map[string]any{"b": []any{json.Number("2.50")}, "a": 1.0}, Default: 10},
+		},
+		"sets": {
+			{Type: TypeSet{TypeString, TypeNull}, Required: []string{"a", "b"}},
+			{Type: TypeSet{TypeNull, TypeString}, Required: []string{"b", "a", "a"}},
+		},
+	}
+	for name, test := range equal {
+		if !Equal(test[0], test[1]) {
+			t.Errorf("%s: expected equal schemas\n%s\n%s", name, &test[0], &test[1])
+		}
+	}
+
+	differ := map[string]func(s *Schema){
+		"title":            func(s *Schema) { s.Title = "order" },
+		"type":             func(s *Schema) { s.Type = TypeSet{TypeObject, TypeNull} },
+		"required":         func(s *Schema) { s.Required = s.Required[:1] },
+		"nested minimum":   func(s *Schema) { *s.Defs["Line"].Properties["qty"].Minimum = "0" },
+		"enum order":       func(s *Schema) { s.Properties["status"].Enum[0], s.Properties["status"].Enum[1] = "closed", "open" },
+		"empty enum":       func(s *Schema) { s.Properties["total"] = Schema{Enum: []any{}} },
+		"additional":       func(s *Schema) { s.AdditionalProperties = nil },
+		"additional true":  func(s *Schema) { s.AdditionalProperties = &True },
+		"multipleOf":       func(s *Schema) { *s.Properties["total"].MultipleOf = "0.1" },
+		"default":          func(s *Schema) { s.Default.(map[string]any)["status"] = "closed" },
+		"example":          func(s *Schema) { s.Examples = append(s.Examples, nil) },
+		"dependency":       func(s *Schema) { s.DependentRequired["total"] = nil },
+		"ref":              func(s *Schema) { s.Properties["lines"].Items.Ref = "#/$defs/line" },
+		"missing property": func(s *Schema) { delete(s.Properties, "total") },
+	}
+	for name, modify := range differ {
+		c := Copy(s)
+		modify(&c)
+		if Equal(s, c) || Equal(c, s) {
+			t.Errorf("%s: expected different schemas\n%s\n%s", name, &s, &c)
+		}
+	}
+}
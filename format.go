@@ -0,0 +1,148 @@
+package jsonschema
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FormatChecker asserts that a string is of a format, e.g. an email address.
+type FormatChecker interface {
+	CheckFormat(s string) error
+}
+
+// FormatCheckerFunc is a function implementing FormatChecker.
+type FormatCheckerFunc func(s string) error
+
+func (f FormatCheckerFunc) CheckFormat(s string) error {
+	return f(s)
+}
+
+var (
+	formatsMu sync.RWMutex
+	formats   = map[string]FormatChecker{
+		"date-time": FormatCheckerFunc(checkDateTime),
+		"email":     FormatCheckerFunc(checkEmail),
+		"uri":       FormatCheckerFunc(checkURI),
+		"uuid":      FormatCheckerFunc(checkUUID),
+		"ipv4":      FormatCheckerFunc(checkIPv4),
+		"ipv6":      FormatCheckerFunc(checkIPv6),
+		"regex":     FormatCheckerFunc(checkRegex),
+	}
+)
+
+// RegisterFormat registers the checker of the format name, which is consulted
+// when validating the format keyword. A registered checker replaces the previous
+// checker of the format, including the checkers of the built-in formats:
+//
+//   - date-time, a date and time as defined by RFC 3339
+//   - email, an address as defined by RFC 5322, without a display name
+//   - uri, an absolute URI as defined by RFC 3986
+//   - uuid, a UUID in its hexadecimal representation
+//   - ipv4 and ipv6, an IP address without a zone
+//   - regex, a regular expression supported by the regexp package
+//
+// RegisterFormat is safe for concurrent use.
+func RegisterFormat(name string, fn func(s string) error) {
+	formatsMu.Lock()
+	defer formatsMu.Unlock()
+	formats[name] = FormatCheckerFunc(fn)
+}
+
+// lookupFormat returns the checker of the format name.
+func lookupFormat(name string) (FormatChecker, bool) {
+	formatsMu.RLock()
+	defer formatsMu.RUnlock()
+	c, ok := formats[name]
+	return c, ok
+}
+
+func checkDateTime(s string) error {
+	// The separator and the UTC designator may be lower case, which time.Parse
+	// does not accept.
+	_, err := time.Parse(time.RFC3339, strings.ToUpper(s))
+	return err
+}
+
+func checkEmail(s string) error {
+	addr, err := mail.ParseAddress(s)
+	if err != nil {
+		return err
+	}
+	if addr.Name != "" || addr.Address != s {
+		return errors.New("not a plain address")
+	}
+	return nil
+}
+
+func checkURI(s string) error {
+	u, err := url.Parse(s)
+	if err != nil {
+		return err
+	}
+	if !u.IsAbs() {
+		return errors.New("missing scheme")
+	}
+	return nil
+}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+func checkUUID(s string) error {
+	if !uuidPattern.MatchString(s) {
+		return errors.New("not of the form xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx")
+	}
+	return nil
+}
+
+func checkIPv4(s string) error {
+	if ip := net.ParseIP(s); ip == nil || ip.To4() == nil || strings.Contains(s, ":") {
+		return errors.New("not a dotted decimal address")
+	}
+	return nil
+}
+
+func checkIPv6(s string) error {
+	if ip := net.ParseIP(s); ip == nil || !strings.Contains(s, ":") {
+		return errors.New("not a colon separated address")
+	}
+	return nil
+}
+
+func checkRegex(s string) error {
+	_, err := regexp.Compile(s)
+	return err
+}
+
+// validateFormat validates a string against the format keyword. An unknown
+// format is an annotation, unless config.StrictFormats is set.
+func (v *validator) validateFormat(s *Schema, str string, kwLoc, instLoc string) ValidationErrors {
+	if s.Format == nil {
+		return nil
+	}
+
+	var msg string
+	if c, ok := lookupFormat(*s.Format); !ok {
+		if !v.config.StrictFormats {
+			return nil
+		}
+		msg = fmt.Sprintf("unknown format %q", *s.Format)
+	} else if err := c.CheckFormat(str); err != nil {
+		msg = fmt.Sprintf("%q is not a valid %s: %s", str, *s.Format, err)
+	} else {
+		return nil
+	}
+
+	return ValidationErrors{{
+		Keyword:          "format",
+		KeywordLocation:  kwLoc + "/format",
+		InstanceLocation: instLoc,
+		Message:          msg,
+	}}
+}
@@ -0,0 +1,106 @@
+package jsonschema_test
+
+import (
+	"errors"
+	"fmt"
+	. "jsonschema"
+	"testing"
+)
+
+func TestSchema_Validate_Format(t *testing.T) {
+	tests := map[string]struct {
+		valid   []string
+		invalid []string
+	}{
+		"date-time": {
+			valid:   []string{"2024-02-29T12:30:00Z", "2024-02-29t12:30:00.5+01:00"},
+			invalid: []string{"2023-02-29T12:30:00Z", "2024-02-29", "2024-02-29T12:30:00"},
+		},
+		"email": {
+			valid:   []string{"joe@example.com", "joe.bloggs+tag@sub.example.com"},
+			invalid: []string{"joe", "Joe <joe@example.com>", "joe@"},
+		},
+		"uri": {
+			valid:   []string{"https://example.com/a?b=c#d", "urn:isbn:0451450523"},
+			invalid: []string{"/relative/path", "http://[::1", "example.com"},
+		},
+		"uuid": {
+			valid:   []string{"2eb8aa08-aa98-11ea-b4aa-73b441d16380", "2EB8AA08-AA98-11EA-B4AA-73B441D16380"},
+			invalid: []string{"2eb8aa08aa9811eab4aa73b441d16380", "2eb8aa08-aa98-11ea-b4aa-73b441d1638", "zzb8aa08-aa98-11ea-b4aa-73b441d16380"},
+		},
+		"ipv4": {
+			valid:   []string{"192.168.0.1", "0.0.0.0"},
+			invalid: []string{"256.0.0.1", "::ffff:192.168.0.1", "192.168.0"},
+		},
+		"ipv6": {
+			valid:   []string{"::1", "2001:db8::ff00:42:8329", "::ffff:192.168.0.1"},
+			invalid: []string{"192.168.0.1", "12345::", "fe80::1%eth0"},
+		},
+		"regex": {
+			valid:   []string{"^[a-z]+$", `\d{2}`},
+			invalid: []string{"(", "[a-"},
+		},
+	}
+
+	for format, test := range tests {
+		t.Run(format, func(t *testing.T) {
+			schema := &Schema{Format: ptr(format)}
+			for _, str := range test.valid {
+				if err := schema.Validate(str); err != nil {
+					t.Errorf("%q: unexpected error: %s", str, err)
+				}
+			}
+			for _, str := range test.invalid {
+				var errs ValidationErrors
+				if err := schema.Validate(str); !errors.As(err, &errs) || len(errs) != 1 || errs[0].Keyword != "format" {
+					t.Errorf("%q: expected format error, have %v", str, err)
+				}
+			}
+
+			// Only strings are asserted.
+			if err := schema.Validate(12.0); err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+		})
+	}
+}
+
+func TestSchema_ValidateWithConfig_StrictFormats(t *testing.T) {
+	schema := &Schema{Format: ptr("x-unknown")}
+	if err := schema.Validate("a"); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	err := schema.ValidateWithConfig(ValidateConfig{StrictFormats: true}, "a")
+	expected := ValidationErrors{{
+		Keyword:         "format",
+		KeywordLocation: "/format",
+		Message:         `unknown format "x-unknown"`,
+	}}
+	if fmt.Sprint(err) != fmt.Sprint(expected) {
+		t.Errorf("\nhave %v\nneed %v", err, expected)
+	}
+}
+
+func TestRegisterFormat(t *testing.T) {
+	RegisterFormat("x-even-length", func(s string) error {
+		if len(s)%2 != 0 {
+			return errors.New("odd length")
+		}
+		return nil
+	})
+
+	schema := &Schema{Format: ptr("x-even-length")}
+	runValidationTests(t, schema, []validationTest{
+		{instance: `"ab"`},
+		{instance: `"abc"`, errs: ValidationErrors{{
+			Keyword:         "format",
+			KeywordLocation: "/format",
+			Message:         `"abc" is not a valid x-even-length: odd length`,
+		}}},
+	})
+
+	if err := schema.ValidateWithConfig(ValidateConfig{StrictFormats: true}, "ab"); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+}
@@ -1,64 +1,587 @@
 package jsonschema
 
 import (
+	"cmp"
+	"encoding"
+	"encoding/json"
 	"fmt"
+	"math"
 	"reflect"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
+var (
+	numMinInt8   = json.Number(strconv.FormatInt(math.MinInt8, 10))
+	numMaxInt8   = json.Number(strconv.FormatInt(math.MaxInt8, 10))
+	numMinInt16  = json.Number(strconv.FormatInt(math.MinInt16, 10))
+	numMaxInt16  = json.Number(strconv.FormatInt(math.MaxInt16, 10))
+	numMinInt32  = json.Number(strconv.FormatInt(math.MinInt32, 10))
+	numMaxInt32  = json.Number(strconv.FormatInt(math.MaxInt32, 10))
+	numMinInt64  = json.Number(strconv.FormatInt(math.MinInt64, 10))
+	numMaxInt64  = json.Number(strconv.FormatInt(math.MaxInt64, 10))
+	numMinUint   = json.Number(strconv.FormatUint(0, 10))
+	numMaxUint8  = json.Number(strconv.FormatUint(math.MaxUint8, 10))
+	numMaxUint16 = json.Number(strconv.FormatUint(math.MaxUint16, 10))
+	numMaxUint32 = json.Number(strconv.FormatUint(math.MaxUint32, 10))
+	numMaxUint64 = json.Number(strconv.FormatUint(math.MaxUint64, 10))
+)
+
+// m contains the predefined schemas for integer kinds, bounded by the range
+// of the respective Go type. The size of int and uint depends on the
+// architecture, they are bounded by the range of int64 and uint64 to generate
+// the same schema on every architecture.
+var m = map[reflect.Kind]Schema{
+	reflect.Int:    {Type: TypeSet{TypeInteger}, Minimum: &numMinInt64, Maximum: &numMaxInt64},
+	reflect.Int8:   {Type: TypeSet{TypeInteger}, Minimum: &numMinInt8, Maximum: &numMaxInt8},
+	reflect.Int16:  {Type: TypeSet{TypeInteger}, Minimum: &numMinInt16, Maximum: &numMaxInt16},
+	reflect.Int32:  {Type: TypeSet{TypeInteger}, Minimum: &numMinInt32, Maximum: &numMaxInt32},
+	reflect.Int64:  {Type: TypeSet{TypeInteger}, Minimum: &numMinInt64, Maximum: &numMaxInt64},
+	reflect.Uint:   {Type: TypeSet{TypeInteger}, Minimum: &numMinUint, Maximum: &numMaxUint64},
+	reflect.Uint8:  {Type: TypeSet{TypeInteger}, Minimum: &numMinUint, Maximum: &numMaxUint8},
+	reflect.Uint16: {Type: TypeSet{TypeInteger}, Minimum: &numMinUint, Maximum: &numMaxUint16},
+	reflect.Uint32: {Type: TypeSet{TypeInteger}, Minimum: &numMinUint, Maximum: &numMaxUint32},
+	reflect.Uint64: {Type: TypeSet{TypeInteger}, Minimum: &numMinUint, Maximum: &numMaxUint64},
+}
+
+// GoTypeConfig configures the schema generation of FromGoTypeWithConfig.
+type GoTypeConfig struct {
+	// ExplicitNull represents the nullability of pointers by a oneOf with a
+	// dedicated {"type":"null"} schema. By default, null is added to the type
+	// set of the schema if possible.
+	ExplicitNull bool
+
+	// OmitEmptyForbidsNull forbids null for pointer fields with the omitempty
+	// option. A nil pointer is omitted, so the property is either absent or not
+	// null. By default, null is allowed as for any other pointer.
+	OmitEmptyForbidsNull bool
+
+	// NullabilityPolicy decides which struct fields allow null, see
+	// NullabilityPolicy. The default is NullabilityPointerOnly.
+	NullabilityPolicy NullabilityPolicy
+
+	// AllowAdditionalProperties does not forbid additional properties for
+	// structs, e.g. to accept objects of future versions of a type. The schema of
+	// a field capturing additional properties is still used.
+	AllowAdditionalProperties bool
+
+	// DetectTextMarshaler describes types implementing encoding.TextMarshaler,
+	// e.g. net.IP, as strings, as they are encoded by their MarshalText method.
+	// Types implementing json.Marshaler are not affected. By default, the schema
+	// is derived from the structure of the type.
+	DetectTextMarshaler bool
+
+	// EmbeddingStyle decides how the fields of embedded structs are described,
+	// see EmbeddingStyle. The default is EmbeddingFlatten.
+	EmbeddingStyle EmbeddingStyle
+
+	// Formats maps types to the schema of their encoding, e.g. a type encoded as a
+	// formatted string. A mapped type is not inspected, the schema is derived from
+	// the FormatSpec instead. Like other named types, a named type is defined in
+	// $defs. Formats takes precedence over SchemaProvider.
+	Formats map[reflect.Type]FormatSpec
+
+	// TagKey is the struct tag key the property names and the omitempty option of
+	// struct fields are read from, it defaults to "json". Another key can be used
+	// to describe an encoding sharing the tag syntax of encoding/json, e.g. "yaml".
+	TagKey string
+
+	// OptionsTagKey is the struct tag key the options of this package are read
+	// from, e.g. `jsonschema:"minimum=0"`, it defaults to "jsonschema".
+	OptionsTagKey string
+
+	// OmitIntBounds omits the maximum of int and uint fields and the minimum of
+	// int fields. Their range depends on the architecture, only the bounds of the
+	// fixed size integer types and the minimum of uint are kept.
+	OmitIntBounds bool
+
+	// IntegerFormats annotates integers with the format "int32" for int32 and
+	// uint32, and "int64" for int64, uint64, int and uint. The bounds of the
+	// integers are kept.
+	IntegerFormats bool
+
+	// PointersOptional treats pointer fields as optional properties, as if they
+	// had the omitempty option. By default, only fields with the omitempty option
+	// are optional. Null is allowed for pointer fields either way.
+	PointersOptional bool
+
+	// RequiredStringsNonEmpty sets a minLength of 1 for required string fields,
+	// i.e. fields without the omitempty option. Strings restricted by an enum,
+	// const, pattern or length, and nullable strings are not affected.
+	RequiredStringsNonEmpty bool
+
+	// DefinitionBaseID assigns every entry of $defs the $id
+	// {DefinitionBaseID}/defs/{Name} and references the definitions by their $id.
+	// By default, definitions have no $id and are referenced by a JSON pointer
+	// relative to the root schema.
+	DefinitionBaseID string
+
+	// Dialect is set as $schema of the root schema, e.g. Draft2020Schema for a
+	// standalone schema document. By default, $schema is omitted.
+	Dialect Dialect
+
+	// BaseID is set as $id of the root schema. By default, $id is omitted.
+	BaseID string
+
+	// InlineDefinitions generates the schema of every named type inline, as if
+	// every field had the inline tag option, so that no $defs are needed. Only a
+	// recursive type is still defined in $defs and referenced where it recurs.
+	InlineDefinitions bool
+
+	// NameFunc returns the name of the definition of a named type in $defs, e.g.
+	// to qualify the names of types of different packages. The name of the type is
+	// used by default. Different types must not share a name.
+	NameFunc func(t reflect.Type) string
+
+	// IncludeField reports whether a struct field is included in the schema. It is
+	// called for every field encoding/json would encode, all fields are included
+	// if IncludeField is nil.
+	IncludeField func(field FieldInfo) bool
+
+	// PostProcess is called in order with the generated schema, including its
+	// $defs, e.g. to rename definitions by RenameDef. Generation fails with the
+	// first error returned.
+	PostProcess []func(s *Schema) error
+}
+
+// NullabilityPolicy decides whether the property of a struct field allows null,
+// depending on whether the field is a pointer and has the omitempty option:
+//
+//	                                pointer  pointer,omitempty  omitempty  neither
+//	NullabilityPointerOnly          null     null               -          -
+//	NullabilityOmitEmptyImpliesNull null     null               null       -
+//	NullabilityStrict               null     -                  -          -
+//
+// NullabilityStrict describes the encoding most precisely, a nil pointer with
+// the omitempty option is omitted and never encoded as null. It is equivalent to
+// GoTypeConfig.OmitEmptyForbidsNull.
+type NullabilityPolicy int
+
+const (
+	NullabilityPointerOnly NullabilityPolicy = iota
+	NullabilityOmitEmptyImpliesNull
+	NullabilityStrict
+)
+
+// EmbeddingStyle decides how the fields promoted from embedded structs are
+// described by the schema of the embedding struct.
+type EmbeddingStyle int
+
+const (
+	// EmbeddingFlatten adds the promoted fields to the properties of the
+	// embedding struct, like encoding/json encodes them.
+	EmbeddingFlatten EmbeddingStyle = iota
+
+	// EmbeddingAllOf describes a struct embedding other structs as an allOf of
+	// references to the embedded structs and an object schema of its own fields:
+	//
+	//	{"allOf": [{"$ref": "#/$defs/Base"}, {"type": "object", "properties": {...}}]}
+	//
+	// Only structs embedded by value are composed, if all of their fields are
+	// promoted, i.e. none is hidden by a field of the same name. Other embedded
+	// structs are flattened, embedded structs with a name in the tag are regular
+	// properties in both styles. The schemas of structs do not forbid additional
+	// properties, as the schema of an embedded struct would reject the fields of
+	// the embedding struct otherwise.
+	EmbeddingAllOf
+)
+
+// QualifiedName names a type by its camel cased import path and its name, e.g.
+// github.com/a/pkg.User is named githubComAPkgUser. It can be used as
+// GoTypeConfig.NameFunc to prevent types of different packages from sharing a
+// definition.
+func QualifiedName(t reflect.Type) string {
+	return pkgToCamel(t.PkgPath()) + t.Name()
+}
+
+// pkgToCamel converts an import path to lower camel case, every character not
+// being a letter or digit starts a new word.
+func pkgToCamel(pkg string) string {
+	words := strings.FieldsFunc(pkg, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var sb strings.Builder
+	for i, w := range words {
+		r, size := utf8.DecodeRuneInString(w)
+		if i == 0 {
+			sb.WriteRune(unicode.ToLower(r))
+		} else {
+			sb.WriteRune(unicode.ToUpper(r))
+		}
+		sb.WriteString(w[size:])
+	}
+	return sb.String()
+}
+
+// FormatSpec describes the schema of a type registered in GoTypeConfig.Formats.
+type FormatSpec struct {
+	// Type is the JSON type of the encoding, e.g. TypeString.
+	Type Type
+	// Format is the value of the format keyword, e.g. "uuid".
+	Format string
+	// Pattern is the optional value of the pattern keyword.
+	Pattern string
+}
+
+func (f FormatSpec) schema() *Schema {
+	s := &Schema{}
+	if f.Type != "" {
+		s.Type = TypeSet{f.Type}
+	}
+	if f.Format != "" {
+		s.Format = ptr(f.Format)
+	}
+	if f.Pattern != "" {
+		s.Pattern = ptr(f.Pattern)
+	}
+	return s
+}
+
+// FieldInfo describes a struct field passed to GoTypeConfig.IncludeField.
+type FieldInfo struct {
+	// Name is the name of the property the field is encoded as.
+	Name string
+	// Struct is the struct type containing the field. Fields promoted from an
+	// embedded struct are reported with the embedding type.
+	Struct reflect.Type
+	// Field is the struct field, its tag may be used to select fields.
+	Field reflect.StructField
+}
+
 type goTypeOptions struct {
-	named map[string]*Schema
+	config GoTypeConfig
+	named  map[string]*Schema
+	types  map[string]reflect.Type
+
+	// inline is set to generate the schema of the next named type inline
+	// instead of referencing its definition, see the inline tag option.
+	// inlining contains the types currently generated inline, a type is not
+	// inlined into itself.
+	inline   bool
+	inlining map[reflect.Type]bool
+}
+
+// defined returns whether a definition for the named type t exists. An error is
+// returned if the name of t is already used by a different type, since both
+// types would share a single definition.
+func (o *goTypeOptions) defined(t reflect.Type) (bool, error) {
+	name := o.name(t)
+	other, ok := o.types[name]
+	switch {
+	case !ok:
+		o.types[name] = t
+		return false, nil
+	case other != t:
+		return false, fmt.Errorf("different types share the definition %q", name)
+	default:
+		_, defined := o.named[name]
+		return defined, nil
+	}
 }
 
+// name returns the name of the definition of the named type t.
+func (o *goTypeOptions) name(t reflect.Type) string {
+	if o.config.NameFunc != nil {
+		return o.config.NameFunc(t)
+	}
+	return t.Name()
+}
+
+// defID returns the $id of the definition with the given name, or an empty
+// string if definitions are not identified.
+func (o *goTypeOptions) defID(name string) string {
+	if o.config.DefinitionBaseID == "" {
+		return ""
+	}
+	return strings.TrimSuffix(o.config.DefinitionBaseID, "/") + "/defs/" + name
+}
+
+// ref returns a schema referencing the definition with the given name.
+func (o *goTypeOptions) ref(name string) *Schema {
+	if id := o.defID(name); id != "" {
+		return &Schema{Ref: id}
+	}
+	return &Schema{Ref: "#/$defs/" + name}
+}
+
+// definition returns the named schema referenced by s, or nil if s does
+// not reference a named schema.
+func (o *goTypeOptions) definition(s *Schema) *Schema {
+	prefix := "#/$defs/"
+	if id := o.defID(""); id != "" {
+		prefix = id
+	}
+	if name, ok := strings.CutPrefix(s.Ref, prefix); ok {
+		return o.named[name]
+	}
+	return nil
+}
+
+// FromGoType returns the schema of the JSON encoding of values of type t. Named
+// struct types are defined in $defs by their name and referenced. A defined type
+// (type B A) gets its own definition, while an alias (type B = A) denotes the same
+// type and therefore shares the definition of A.
+//
+// The properties of struct fields are required unless the field has the
+// omitempty option or the omitzero option of Go 1.24, the options are treated
+// alike.
+//
+// A field with the struct tag `jsonschema:"inline"` of a named type, or a pointer
+// to one, contains the schema of the type instead of a reference to its
+// definition.
+//
+// A string keyed map field with the struct tag `jsonschema:"additionalProperties"`
+// is not a property, its value schema is used as additionalProperties of the
+// struct instead. Such a field may be excluded from the encoding using `json:"-"`.
 func FromGoType(t reflect.Type) (*Schema, error) {
-	opts := &goTypeOptions{named: make(map[string]*Schema)}
+	return FromGoTypeWithConfig(GoTypeConfig{}, t)
+}
+
+// FromGoTypeWithConfig is like FromGoType, but allows to configure the schema
+// generation.
+func FromGoTypeWithConfig(config GoTypeConfig, t reflect.Type) (*Schema, error) {
+	opts := &goTypeOptions{
+		config:   config,
+		named:    make(map[string]*Schema),
+		types:    make(map[string]reflect.Type),
+		inlining: make(map[reflect.Type]bool),
+	}
 	s, err := fromGoType(t, opts)
 	if err != nil {
 		return nil, err
 	}
 
+	// The root of a defined type is a reference to its definition, $schema and
+	// $id belong to the reference rather than to the definition.
+	if config.Dialect != "" {
+		s.Schema = string(config.Dialect)
+	}
+	if config.BaseID != "" {
+		s.ID = config.BaseID
+	}
+
 	if len(opts.named) != 0 {
-		s.Defs = make(map[string]Schema)
+		s.Defs = make(map[string]Schema, len(opts.named))
 		for k, v := range opts.named {
+			if id := opts.defID(k); id != "" {
+				v.ID = id
+			}
 			s.Defs[k] = *v
 		}
 	}
+
+	for i, fn := range config.PostProcess {
+		if err = fn(s); err != nil {
+			return nil, fmt.Errorf("schema.FromGoType: post-processor %d: %w", i, err)
+		}
+	}
 	return s, nil
 }
 
-func newTyped(t Type, nullable bool) *Schema {
-	s := Schema{}
-	s.Type = TypeSet{t}
-	if nullable {
+// SchemaProvider is implemented by types that provide their own schema. FromGoType
+// uses the schema returned by JSONSchema instead of deriving one, the method is
+// called on the zero value of the type.
+//
+// A type with a custom encoding, e.g. a struct encoded as a fixed length array,
+// can use prefixItems to describe the schema of each item.
+type SchemaProvider interface {
+	JSONSchema() *Schema
+}
+
+var (
+	schemaProviderType = reflect.TypeOf((*SchemaProvider)(nil)).Elem()
+	rawMessageType     = reflect.TypeOf(json.RawMessage(nil))
+	numberType         = reflect.TypeOf(json.Number(""))
+	marshalerType      = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	timeType           = reflect.TypeOf(time.Time{})
+	textMarshalerType  = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// providedSchema returns a copy of the schema provided by t, if t or a pointer
+// to t implements SchemaProvider.
+func providedSchema(t reflect.Type) (*Schema, bool) {
+	if t.Kind() == reflect.Interface || !reflect.PointerTo(t).Implements(schemaProviderType) {
+		return nil, false
+	}
+
+	p := reflect.New(t).Interface().(SchemaProvider).JSONSchema()
+	if p == nil {
+		p = &True
+	}
+	return p.Clone(), true
+}
+
+// predefined returns the schema s of t, which is not derived from t. If t is a
+// named type, s is defined by its name and referenced, unless inlined.
+func (o *goTypeOptions) predefined(t reflect.Type, s *Schema, inline bool) (*Schema, error) {
+	if t.Name() == "" || inline {
+		return s, nil
+	}
+
+	defined, err := o.defined(t)
+	if err != nil {
+		return nil, fmt.Errorf("schema.FromGoType: %w", err)
+	}
+	if !defined {
+		o.named[o.name(t)] = s
+	}
+	return o.ref(o.name(t)), nil
+}
+
+// withNull returns a schema that additionally allows null, s is returned if it or
+// the definition it references already allows null. The type set is
+// extended if possible, otherwise or if ExplicitNull is set, the schema is
+// combined with a null schema.
+func (o *goTypeOptions) withNull(s *Schema) *Schema {
+	if def := o.definition(s); def != nil && def.IsNullable() {
+		return s
+	}
+
+	switch {
+	case s.IsTrue() || s.IsNullable():
+		return s
+	case !o.config.ExplicitNull && s.Ref == "" && len(s.Type) > 0 && s.Const == nil && s.Enum == nil:
 		s.Type = append(s.Type, TypeNull)
+		return s
+	default:
+		return &Schema{OneOf: []Schema{*s, {Type: TypeSet{TypeNull}}}}
 	}
-	return &s
+}
+
+// implements returns whether t or a pointer to t implements the interface type
+// iface.
+func implements(t, iface reflect.Type) bool {
+	return t.Implements(iface) || reflect.PointerTo(t).Implements(iface)
+}
+
+func timeSchema() *Schema {
+	return &Schema{Type: TypeSet{TypeString}, Format: ptr("date-time")}
+}
+
+// embedsTime returns whether t is a struct encoded using the MarshalJSON method
+// promoted from an embedded time.Time or *time.Time.
+func embedsTime(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct || !t.Implements(marshalerType) {
+		return false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous && (f.Type == timeType || f.Type == reflect.PointerTo(timeType)) {
+			return true
+		}
+	}
+	return false
+}
+
+// isPlainString returns whether s only allows strings without restricting them
+// further by an enum, const, pattern or length.
+func isPlainString(s *Schema) bool {
+	return slices.Equal(s.Type, TypeSet{TypeString}) && s.Ref == "" && s.Enum == nil && s.Const == nil &&
+		s.Pattern == nil && s.MinLength == nil && s.MaxLength == nil
+}
+
+func newTyped(t Type) *Schema {
+	return &Schema{Type: TypeSet{t}}
 }
 
 func fromGoType(t reflect.Type, opts *goTypeOptions) (*Schema, error) {
+	// Every level of indirection may be nil, but null is only added once.
 	nullable := false
-	if t.Kind() == reflect.Ptr {
+	for t.Kind() == reflect.Ptr {
 		nullable = true
 		t = t.Elem()
 	}
 
+	s, err := fromGoValueType(t, opts)
+	if err != nil || !nullable {
+		return s, err
+	}
+	return opts.withNull(s), nil
+}
+
+// fromGoValueType returns the schema of the non-pointer type t, null is not allowed
+// unless the schema is provided by t.
+func fromGoValueType(t reflect.Type, opts *goTypeOptions) (*Schema, error) {
+	inline := (opts.inline || opts.config.InlineDefinitions) && !opts.inlining[t]
+	opts.inline = false
+	if inline {
+		opts.inlining[t] = true
+		defer delete(opts.inlining, t)
+	}
+
+	if spec, ok := opts.config.Formats[t]; ok {
+		return opts.predefined(t, spec.schema(), inline)
+	}
+	if s, ok := providedSchema(t); ok {
+		return opts.predefined(t, s, inline)
+	}
+
+	// A time is encoded in RFC 3339 format, so is a struct embedding a time, as the
+	// methods of the time are promoted.
+	if t == timeType {
+		return timeSchema(), nil
+	}
+	if embedsTime(t) {
+		return opts.predefined(t, timeSchema(), inline)
+	}
+
+	if opts.config.DetectTextMarshaler && implements(t, textMarshalerType) && !implements(t, marshalerType) {
+		return opts.predefined(t, newTyped(TypeString), inline)
+	}
+
+	// The raw encoding is copied verbatim and may be any JSON value, a number is
+	// encoded as a number literal despite being a string.
+	switch t {
+	case rawMessageType:
+		s := Copy(True)
+		return &s, nil
+	case numberType:
+		return newTyped(TypeNumber), nil
+	}
+
 	switch t.Kind() {
 	case reflect.Bool:
-		return newTyped(TypeBoolean, nullable), nil
+		return newTyped(TypeBoolean), nil
 	case reflect.String:
-		return newTyped(TypeString, nullable), nil
+		return newTyped(TypeString), nil
 	case reflect.Float32, reflect.Float64:
-		return newTyped(TypeNumber, nullable), nil
+		return newTyped(TypeNumber), nil
 
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8,
 		reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		s := m[t.Kind()]
-		if nullable {
-			s.Type = append(s.Type, TypeNull)
+		// The predefined schemas share their bounds with the package variables, so
+		// they are copied to keep the returned schema independent.
+		s := Copy(m[t.Kind()])
+		if opts.config.OmitIntBounds && (t.Kind() == reflect.Int || t.Kind() == reflect.Uint) {
+			s.Maximum = nil
+			if t.Kind() == reflect.Int {
+				s.Minimum = nil
+			}
+		}
+		if opts.config.IntegerFormats {
+			switch t.Kind() {
+			case reflect.Int32, reflect.Uint32:
+				s.Format = ptr("int32")
+			case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+				s.Format = ptr("int64")
+			}
 		}
 		return &s, nil
 	case reflect.Array, reflect.Slice:
-		s := newTyped(TypeArray, nullable)
+		// A byte slice is encoded as a base64 string, unless the bytes have a
+		// custom encoding.
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 &&
+			!implements(t.Elem(), marshalerType) && !implements(t.Elem(), textMarshalerType) {
+			return opts.predefined(t, &Schema{Type: TypeSet{TypeString}, ContentEncoding: ptr("base64")}, inline)
+		}
+
+		s := newTyped(TypeArray)
 
 		if t.Kind() == reflect.Array {
 			s.MaxItems = ptr(t.Len())
@@ -70,71 +593,110 @@ func fromGoType(t reflect.Type, opts *goTypeOptions) (*Schema, error) {
 		}
 		return s, nil
 	case reflect.Struct:
-		var defined bool
-		if _, defined = opts.named[t.Name()]; defined {
-			return &Schema{Ref: "#/$defs/" + t.Name()}, nil
+		s := newTyped(TypeObject)
+		if t.Name() != "" {
+			defined, err := opts.defined(t)
+			if err != nil {
+				return nil, fmt.Errorf("schema.FromGoType: %w", err)
+			}
+			// An inlined schema is generated independent of the definition, which
+			// is still used for recursive references and other fields.
+			if defined && !inline {
+				return opts.ref(opts.name(t)), nil
+			}
+			if !inline {
+				opts.named[opts.name(t)] = s
+			}
 		}
 
-		s := newTyped(TypeObject, nullable)
-		if t.Name() != "" {
-			opts.named[t.Name()] = s
+		if !opts.config.AllowAdditionalProperties && opts.config.EmbeddingStyle != EmbeddingAllOf {
+			s.AdditionalProperties = &False
 		}
 
-		s.AdditionalProperties = &False
+		fields, extra, err := additionalPropertiesField(opts.includedFields(t, opts.fields(t)))
+		if err != nil {
+			return nil, fmt.Errorf("schema.FromGoType: %w", err)
+		}
 
-		num := t.NumField()
-		s.Properties = make(map[string]Schema, num)
-		for i := 0; i < num; i++ {
-			field := t.Field(i)
-			if field.Anonymous {
-				return nil, fmt.Errorf("embedded struct fields are not yet supported")
+		var bases []reflect.Type
+		if opts.config.EmbeddingStyle == EmbeddingAllOf {
+			bases, fields = opts.embeddedBases(t, fields)
+		}
+		if extra != nil {
+			if s.AdditionalProperties, err = fromGoType(extra, opts); err != nil {
+				return nil, fmt.Errorf("schema.FromGoType: %w", err)
 			}
+		}
 
-			fieldType := field.Type
+		s.Properties = make(map[string]Schema, len(fields))
+		for _, f := range fields {
+			ft := f.typ
+			forbidsNull := opts.config.OmitEmptyForbidsNull || opts.config.NullabilityPolicy == NullabilityStrict
+			if forbidsNull && f.omitEmpty && ft.Kind() == reflect.Ptr {
+				// A nil pointer is omitted, so the field is never encoded as null.
+				ft = ft.Elem()
+			}
 
 			var (
 				fs  *Schema
 				err error
 			)
-			if recStruct(t, fieldType) {
-				fs, err = &Schema{Ref: "#/$defs/" + t.Name()}, nil
+			if recStruct(t, ft) && !inline {
+				// A pointer to the struct itself may be nil like any other pointer.
+				if fs, err = opts.ref(opts.name(t)), nil; ft.Kind() == reflect.Ptr {
+					fs = opts.withNull(fs)
+				}
+			} else if qs, ok := opts.quoted(f, ft); ok {
+				fs = qs
 			} else {
-				fs, err = fromGoType(fieldType, opts)
+				opts.inline = hasOption(f.options, "inline")
+				fs, err = fromGoType(ft, opts)
 			}
 			if err != nil {
 				return nil, fmt.Errorf("schema.FromGoType: %w", err)
 			}
+			if opts.config.NullabilityPolicy == NullabilityOmitEmptyImpliesNull && f.omitEmpty {
+				fs = opts.withNull(fs)
+			}
+
+			if err = applyTagOptions(f, fs, opts); err != nil {
+				return nil, fmt.Errorf("schema.FromGoType: field %s: %w", f.name, err)
+			}
 
-			var name string
-			jsonTag := field.Tag.Get("json")
-			if jsonTag != "" {
-				parts := strings.Split(jsonTag, ",")
-				if parts[0] == "" {
-					name = field.Name
-				} else {
-					name = parts[0]
+			// Fields promoted through an embedded struct pointer are only present
+			// if the pointer is not nil, see dependentRequired.
+			if !opts.optional(f) && f.optIndex == nil {
+				s.Required = append(s.Required, f.name)
+
+				if opts.config.RequiredStringsNonEmpty && isPlainString(fs) {
+					fs.MinLength = ptr(1)
 				}
-			} else {
-				name = field.Name
 			}
 
-			s.Properties[name] = *fs
+			s.Properties[f.name] = *fs
+		}
+		s.DependentRequired = opts.dependentRequired(fields)
 
-			if !strings.Contains(jsonTag, ",omitempty") {
-				s.Required = append(s.Required, name)
+		if len(bases) > 0 {
+			own := *s
+			*s = Schema{}
+			for _, b := range bases {
+				bs, err := fromGoType(b, opts)
+				if err != nil {
+					return nil, fmt.Errorf("schema.FromGoType: %w", err)
+				}
+				s.AllOf = append(s.AllOf, *bs)
 			}
+			s.AllOf = append(s.AllOf, own)
 		}
 
-		if t.Name() != "" {
-			return &Schema{Ref: "#/$defs/" + t.Name()}, nil
+		if t.Name() != "" && !inline {
+			return opts.ref(opts.name(t)), nil
 		}
 		return s, nil
 	case reflect.Map:
 		s := Schema{}
 		s.Type = TypeSet{TypeObject}
-		if nullable {
-			s.Type = append(s.Type, TypeNull)
-		}
 
 		keyType, valType := t.Key(), t.Elem()
 		if keyType.Kind() != reflect.String {
@@ -150,18 +712,117 @@ func fromGoType(t reflect.Type, opts *goTypeOptions) (*Schema, error) {
 			return newMapSchema(ks, vs), nil
 		}
 
+		// A named key type may restrict the keys, e.g. by an enum, which is
+		// applied to the property names.
+		if keyType.Name() != "" && keyType.PkgPath() != "" {
+			ks, err := fromGoType(keyType, opts)
+			if err != nil {
+				return nil, fmt.Errorf("schema.FromGoType: %w", err)
+			}
+			if !isPlainString(ks) {
+				s.PropertyNames = ks
+			}
+		}
+
 		propertyArchetype, err := fromGoType(valType, opts)
 		if err != nil {
 			return nil, fmt.Errorf("schema.FromGoType: %w", err)
 		}
 		s.AdditionalProperties = propertyArchetype
 
+		return &s, nil
+	case reflect.Interface:
+		// An interface can hold any value, including nil.
+		s := Copy(True)
 		return &s, nil
 	default:
 		return nil, fmt.Errorf("cannot map Go type: %v", t)
 	}
 }
 
+// quoted returns the schema of a field with the string option, the type ft of the
+// field may be a pointer. The option has no effect on types with a schema of their
+// own or a custom encoding.
+func (o *goTypeOptions) quoted(f field, ft reflect.Type) (*Schema, bool) {
+	nullable := ft.Kind() == reflect.Ptr
+	if nullable {
+		ft = ft.Elem()
+	}
+	if !f.quoted || o.config.Formats[ft] != (FormatSpec{}) || implements(ft, schemaProviderType) ||
+		implements(ft, marshalerType) || implements(ft, textMarshalerType) {
+		return nil, false
+	}
+
+	s := newTyped(TypeString)
+	switch ft.Kind() {
+	case reflect.Bool:
+		s.Enum = []any{"true", "false"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		s.Pattern = ptr(`^-?(0|[1-9][0-9]*)$`)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		s.Pattern = ptr(`^(0|[1-9][0-9]*)$`)
+	case reflect.Float32, reflect.Float64:
+		s.Pattern = ptr(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][-+]?[0-9]+)?$`)
+	}
+
+	if nullable {
+		return o.withNull(s), true
+	}
+	return s, true
+}
+
+// embeddedBases returns the structs embedded by value in t that are composed by
+// EmbeddingAllOf, and the fields of t without the fields promoted from them.
+func (o *goTypeOptions) embeddedBases(t reflect.Type, fields []field) ([]reflect.Type, []field) {
+	var (
+		bases   []reflect.Type
+		indexes []int
+	)
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if name, _, _ := strings.Cut(sf.Tag.Get(o.tagKey()), ","); !sf.Anonymous || sf.Type.Kind() != reflect.Struct || name != "" {
+			continue
+		}
+
+		// The embedded struct is only composed if its schema describes exactly
+		// the promoted fields.
+		baseFields, extra, err := additionalPropertiesField(o.includedFields(sf.Type, o.fields(sf.Type)))
+		if err != nil || extra != nil {
+			continue
+		}
+		promoted := 0
+		for _, f := range fields {
+			if len(f.index) > 1 && f.index[0] == i {
+				promoted++
+			}
+		}
+		if promoted == 0 || promoted != len(baseFields) {
+			continue
+		}
+
+		bases = append(bases, sf.Type)
+		indexes = append(indexes, i)
+	}
+
+	if len(bases) == 0 {
+		return nil, fields
+	}
+	return bases, slices.DeleteFunc(slices.Clone(fields), func(f field) bool {
+		return len(f.index) > 1 && slices.Contains(indexes, f.index[0])
+	})
+}
+
+// includedFields returns the fields of the struct type t accepted by the
+// IncludeField function of the config.
+func (o *goTypeOptions) includedFields(t reflect.Type, fields []field) []field {
+	if o.config.IncludeField == nil {
+		return fields
+	}
+	return slices.DeleteFunc(slices.Clone(fields), func(f field) bool {
+		return !o.config.IncludeField(FieldInfo{Name: f.name, Struct: t, Field: t.FieldByIndex(f.index)})
+	})
+}
+
 func ptr[T any](v T) *T {
 	return &v
 }
@@ -173,7 +834,7 @@ func recStruct(t, t2 reflect.Type) bool {
 	if t2.Kind() == reflect.Ptr {
 		t2 = t2.Elem()
 	}
-	return t.AssignableTo(t2)
+	return t == t2
 }
 
 func newMapSchema(keyType, valueType *Schema) *Schema {
@@ -194,3 +855,308 @@ func newMapSchema(keyType, valueType *Schema) *Schema {
 		AdditionalProperties: &False,
 	}
 }
+
+// field represents a single (possibly promoted) struct field as seen by
+// encoding/json.
+type field struct {
+	name    string
+	tagged  bool
+	index   []int
+	typ     reflect.Type
+	options []tagOption
+
+	// omitEmpty is set by the omitempty and the omitzero option, either way the
+	// field may be omitted by encoding/json.
+	omitEmpty bool
+
+	// quoted is set by the string option for fields of a boolean, numeric or
+	// string type, which encoding/json encodes as a JSON string.
+	quoted bool
+
+	// optIndex is the index sequence of the innermost embedded struct pointer
+	// the field is promoted through, or nil if there is none.
+	optIndex []int
+}
+
+// tagKey returns the struct tag key of the encoding the schema is generated for.
+func (o *goTypeOptions) tagKey() string {
+	if o.config.TagKey == "" {
+		return "json"
+	}
+	return o.config.TagKey
+}
+
+// optionsTagKey returns the struct tag key the options of this package are read
+// from.
+func (o *goTypeOptions) optionsTagKey() string {
+	if o.config.OptionsTagKey == "" {
+		return "jsonschema"
+	}
+	return o.config.OptionsTagKey
+}
+
+// fields returns the fields of the struct type t, see typeFields.
+func (o *goTypeOptions) fields(t reflect.Type) []field {
+	return cachedTypeFields(t, o.tagKey(), o.optionsTagKey())
+}
+
+// fieldCacheKey identifies the fields of a struct type read using a tag key and an
+// options tag key.
+type fieldCacheKey struct {
+	t                  reflect.Type
+	key, optionsTagKey string
+}
+
+// fieldCache caches the fields of struct types, see cachedTypeFields.
+var fieldCache sync.Map // map[fieldCacheKey][]field
+
+// cachedTypeFields is like typeFields but caches the result per type and tag keys.
+// The returned slice is shared and must not be modified.
+func cachedTypeFields(t reflect.Type, key, optionsTagKey string) []field {
+	k := fieldCacheKey{t, key, optionsTagKey}
+	if f, ok := fieldCache.Load(k); ok {
+		return f.([]field)
+	}
+	f, _ := fieldCache.LoadOrStore(k, typeFields(t, key, optionsTagKey))
+	return f.([]field)
+}
+
+// typeFields returns the fields encoding/json would encode for the struct type t,
+// reading the names and the omitempty option from the struct tag key and the
+// options of this package from optionsTagKey. Fields of embedded
+// structs are promoted following the same visibility rules, dropping ambiguous
+// fields. The algorithm is a breadth-first search over the embedded structs,
+// adapted from encoding/json.
+func typeFields(t reflect.Type, key, optionsTagKey string) []field {
+	var (
+		current []field
+		next    = []field{{typ: t}}
+
+		count, nextCount map[reflect.Type]int
+		visited          = map[reflect.Type]bool{}
+
+		fields []field
+	)
+
+	for len(next) > 0 {
+		current, next = next, current[:0]
+		count, nextCount = nextCount, map[reflect.Type]int{}
+
+		for _, f := range current {
+			if visited[f.typ] {
+				continue
+			}
+			visited[f.typ] = true
+
+			for i := 0; i < f.typ.NumField(); i++ {
+				sf := f.typ.Field(i)
+				if sf.Anonymous {
+					et := sf.Type
+					if et.Kind() == reflect.Ptr {
+						et = et.Elem()
+					}
+					// Embedded fields of unexported non-struct types are ignored,
+					// unexported struct types may still promote exported fields.
+					if !sf.IsExported() && et.Kind() != reflect.Struct {
+						continue
+					}
+				} else if !sf.IsExported() {
+					continue
+				}
+
+				// A field capturing additional properties is usually excluded from the
+				// encoding and handled by a custom MarshalJSON.
+				options := parseTagOptions(sf.Tag.Get(optionsTagKey))
+				tag := sf.Tag.Get(key)
+				if tag == "-" {
+					if !hasOption(options, "additionalProperties") {
+						continue
+					}
+					tag = ""
+				}
+				name, opts, _ := strings.Cut(tag, ",")
+
+				index := make([]int, len(f.index)+1)
+				copy(index, f.index)
+				index[len(f.index)] = i
+
+				ft := sf.Type
+				if ft.Name() == "" && ft.Kind() == reflect.Ptr {
+					ft = ft.Elem()
+				}
+
+				if name != "" || !sf.Anonymous || ft.Kind() != reflect.Struct {
+					field := field{
+						name:      name,
+						tagged:    name != "",
+						index:     index,
+						typ:       sf.Type,
+						omitEmpty: hasTagOption(opts, "omitempty") || hasTagOption(opts, "omitzero"),
+						options:   options,
+						optIndex:  f.optIndex,
+					}
+					if hasTagOption(opts, "string") {
+						switch ft.Kind() {
+						case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+							reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+							reflect.Float32, reflect.Float64, reflect.String:
+							field.quoted = true
+						}
+					}
+					if field.name == "" {
+						field.name = sf.Name
+					}
+					fields = append(fields, field)
+
+					// If there were multiple instances, add a second, so that the
+					// annihilation code will see a duplicate.
+					if count[f.typ] > 1 {
+						fields = append(fields, fields[len(fields)-1])
+					}
+					continue
+				}
+
+				// The tag options of an embedded struct without a name are ignored by
+				// encoding/json, an omitempty option in particular has no effect. The
+				// promoted fields are omitted only if the embedded pointer is nil.
+				nextCount[ft]++
+				if nextCount[ft] == 1 {
+					optIndex := f.optIndex
+					if sf.Type.Kind() == reflect.Ptr {
+						optIndex = index
+					}
+					next = append(next, field{name: ft.Name(), index: index, typ: ft, optIndex: optIndex})
+				}
+			}
+		}
+	}
+
+	slices.SortFunc(fields, func(a, b field) int {
+		if c := strings.Compare(a.name, b.name); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(len(a.index), len(b.index)); c != 0 {
+			return c
+		}
+		if a.tagged != b.tagged {
+			if a.tagged {
+				return -1
+			}
+			return 1
+		}
+		return slices.Compare(a.index, b.index)
+	})
+
+	// Remove the fields hidden by Go's embedding rules, keeping the dominant
+	// field for each name, if there is one.
+	out := fields[:0]
+	for advance, i := 0, 0; i < len(fields); i += advance {
+		fi := fields[i]
+		for advance = 1; i+advance < len(fields); advance++ {
+			if fields[i+advance].name != fi.name {
+				break
+			}
+		}
+		if advance == 1 {
+			out = append(out, fi)
+			continue
+		}
+		if dominant, ok := dominantField(fields[i : i+advance]); ok {
+			out = append(out, dominant)
+		}
+	}
+
+	fields = out
+	slices.SortFunc(fields, func(a, b field) int {
+		return slices.Compare(a.index, b.index)
+	})
+	return fields
+}
+
+// dominantField returns the field that hides all other fields with the same
+// name, if there is one. The fields must be sorted by depth and tagged-ness.
+func dominantField(fields []field) (field, bool) {
+	if len(fields) > 1 && len(fields[0].index) == len(fields[1].index) && fields[0].tagged == fields[1].tagged {
+		return field{}, false
+	}
+	return fields[0], true
+}
+
+func hasTagOption(opts, option string) bool {
+	for opts != "" {
+		var o string
+		o, opts, _ = strings.Cut(opts, ",")
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
+
+// additionalPropertiesField removes the field tagged with the additionalProperties
+// option from fields and returns the value type of the map it is declared as. The
+// value type is nil if there is no such field.
+func additionalPropertiesField(fields []field) ([]field, reflect.Type, error) {
+	var extra *field
+	for i := range fields {
+		if !hasOption(fields[i].options, "additionalProperties") {
+			continue
+		}
+		if extra != nil {
+			return nil, nil, fmt.Errorf("fields %s and %s both capture additional properties", extra.name, fields[i].name)
+		}
+		extra = &fields[i]
+	}
+	if extra == nil {
+		return fields, nil, nil
+	}
+
+	t := extra.typ
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Map || t.Key().Kind() != reflect.String {
+		return nil, nil, fmt.Errorf("field %s: option %q requires a string keyed map", extra.name, "additionalProperties")
+	}
+
+	return slices.DeleteFunc(slices.Clone(fields), func(f field) bool {
+		return hasOption(f.options, "additionalProperties")
+	}), t.Elem(), nil
+}
+
+// optional returns whether the property of f may be absent, because f is omitted
+// if empty or is a pointer and PointersOptional is set.
+func (o *goTypeOptions) optional(f field) bool {
+	return f.omitEmpty || (o.config.PointersOptional && f.typ.Kind() == reflect.Ptr)
+}
+
+// dependentRequired returns the dependencies between fields promoted through
+// embedded struct pointers. If such a field is present, the embedded pointer
+// (and all pointers it is embedded in) is not nil, so every field promoted through
+// one of these pointers must be present too, unless it is optional.
+func (o *goTypeOptions) dependentRequired(fields []field) map[string][]string {
+	var deps map[string][]string
+	for _, f := range fields {
+		if f.optIndex == nil {
+			continue
+		}
+
+		var names []string
+		for _, g := range fields {
+			if o.optional(g) || g.optIndex == nil || g.name == f.name {
+				continue
+			}
+			if len(g.optIndex) <= len(f.index) && slices.Equal(f.index[:len(g.optIndex)], g.optIndex) {
+				names = append(names, g.name)
+			}
+		}
+
+		if len(names) > 0 {
+			if deps == nil {
+				deps = make(map[string][]string)
+			}
+			deps[f.name] = names
+		}
+	}
+	return deps
+}
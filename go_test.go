@@ -1,14 +1,33 @@
 package jsonschema_test
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"go/token"
 	. "jsonschema"
 	"math"
+	"net"
+	"path"
 	"reflect"
+	"slices"
 	"strconv"
+	"strings"
 	"testing"
+	"text/scanner"
+	"time"
 )
 
+type StrManyPtr ***string
+
+// NullableMoney is a SchemaProvider whose schema already allows null.
+type NullableMoney struct{}
+
+func (NullableMoney) JSONSchema() *Schema {
+	return &Schema{OneOf: []Schema{{Type: TypeSet{TypeString}}, {Type: TypeSet{TypeNull}}}}
+}
+
 func TestFromGoType_Primitives(t *testing.T) {
 	var (
 		uint8min = json.Number(strconv.FormatUint(0, 10))
@@ -28,6 +47,19 @@ func TestFromGoType_Primitives(t *testing.T) {
 		{In: uint8(0), Out: &Schema{Type: TypeSet{TypeInteger}, Minimum: &uint8min, Maximum: &uint8max}},
 		{In: ptr(uint8(0)), Out: &Schema{Type: TypeSet{TypeInteger, TypeNull}, Minimum: &uint8min, Maximum: &uint8max}},
 		{In: int16(0), Out: &Schema{Type: TypeSet{TypeInteger}, Minimum: &int16min, Maximum: &int16max}},
+		{In: ptr(ptr("")), Out: &Schema{Type: TypeSet{TypeString, TypeNull}}},
+		{In: ptr(ptr(ptr(int16(0)))), Out: &Schema{Type: TypeSet{TypeInteger, TypeNull}, Minimum: &int16min, Maximum: &int16max}},
+		{In: StrManyPtr(nil), Out: &Schema{Type: TypeSet{TypeString, TypeNull}}},
+		{In: ptr(StrManyPtr(nil)), Out: &Schema{Type: TypeSet{TypeString, TypeNull}}},
+		{In: ptr(ptr(StrManyPtr(nil))), Out: &Schema{Type: TypeSet{TypeString, TypeNull}}},
+		{In: ptr(ptr(Money{})), Out: &Schema{
+			OneOf: []Schema{{Ref: "#/$defs/Money"}, {Type: TypeSet{TypeNull}}},
+			Defs:  map[string]Schema{"Money": *Money{}.JSONSchema()},
+		}},
+		{In: ptr(NullableMoney{}), Out: &Schema{
+			Ref:  "#/$defs/NullableMoney",
+			Defs: map[string]Schema{"NullableMoney": *NullableMoney{}.JSONSchema()},
+		}},
 	}
 
 	for _, test := range tests {
@@ -47,8 +79,8 @@ func TestFromGoType(t *testing.T) {
 	var (
 		uint8min = json.Number(strconv.FormatUint(0, 10))
 		uint8max = json.Number(strconv.FormatUint(math.MaxUint8, 10))
-		intMin   = json.Number(strconv.FormatInt(math.MinInt, 10))
-		intMax   = json.Number(strconv.FormatInt(math.MaxInt, 10))
+		intMin   = json.Number(strconv.FormatInt(math.MinInt64, 10))
+		intMax   = json.Number(strconv.FormatInt(math.MaxInt64, 10))
 	)
 
 	type Comment struct {
@@ -217,3 +249,2373 @@ func TestFromGoType(t *testing.T) {
 		})
 	}
 }
+
+func TestFromGoType_Embedded(t *testing.T) {
+	var (
+		intMin = json.Number(strconv.FormatInt(math.MinInt64, 10))
+		intMax = json.Number(strconv.FormatInt(math.MaxInt64, 10))
+	)
+
+	type Base struct {
+		ID   string `json:"id"`
+		Note string `json:"note,omitempty"`
+	}
+
+	type Audit struct {
+		CreatedBy string `json:"createdBy"`
+		UpdatedBy string `json:"updatedBy,omitempty"`
+	}
+
+	type Trace struct {
+		TraceID  string `json:"traceID"`
+		ParentID string `json:"parentID,omitzero"`
+	}
+
+	type Inner struct {
+		Depth string `json:"depth"`
+	}
+
+	type Outer struct {
+		*Inner
+		Level string `json:"level"`
+	}
+
+	type hidden struct {
+		Visible string `json:"visible"`
+		secret  string
+	}
+
+	tests := map[string]struct {
+		In  any
+		Out *Schema
+	}{
+		"embedded struct": {
+			In: struct {
+				Base
+				Name string `json:"name"`
+			}{},
+			Out: &Schema{
+				Type: TypeSet{TypeObject},
+				Properties: map[string]Schema{
+					"id":   {Type: TypeSet{TypeString}},
+					"note": {Type: TypeSet{TypeString}},
+					"name": {Type: TypeSet{TypeString}},
+				},
+				AdditionalProperties: &False,
+				Required:             []string{"id", "name"},
+			},
+		},
+		"embedded struct with omitempty": {
+			In: struct {
+				Base `json:",omitempty"`
+			}{},
+			Out: &Schema{
+				Type: TypeSet{TypeObject},
+				Properties: map[string]Schema{
+					"id":   {Type: TypeSet{TypeString}},
+					"note": {Type: TypeSet{TypeString}},
+				},
+				AdditionalProperties: &False,
+				Required:             []string{"id"},
+			},
+		},
+		"embedded struct ptr with omitempty fields": {
+			In: struct {
+				*Audit
+				Name string `json:"name"`
+			}{},
+			Out: &Schema{
+				Type: TypeSet{TypeObject},
+				Properties: map[string]Schema{
+					"createdBy": {Type: TypeSet{TypeString}},
+					"updatedBy": {Type: TypeSet{TypeString}},
+					"name":      {Type: TypeSet{TypeString}},
+				},
+				AdditionalProperties: &False,
+				Required:             []string{"name"},
+				DependentRequired: map[string][]string{
+					"updatedBy": {"createdBy"},
+				},
+			},
+		},
+		"omitempty embedded struct ptr": {
+			In: struct {
+				*Base  `json:",omitempty"`
+				*Audit `json:",omitempty"`
+			}{},
+			Out: &Schema{
+				Type: TypeSet{TypeObject},
+				Properties: map[string]Schema{
+					"id":        {Type: TypeSet{TypeString}},
+					"note":      {Type: TypeSet{TypeString}},
+					"createdBy": {Type: TypeSet{TypeString}},
+					"updatedBy": {Type: TypeSet{TypeString}},
+				},
+				AdditionalProperties: &False,
+				DependentRequired: map[string][]string{
+					"note":      {"id"},
+					"updatedBy": {"createdBy"},
+				},
+			},
+		},
+		"embedded struct ptr with omitzero fields": {
+			In: struct {
+				*Trace
+				Name string `json:"name,omitzero"`
+			}{},
+			Out: &Schema{
+				Type: TypeSet{TypeObject},
+				Properties: map[string]Schema{
+					"traceID":  {Type: TypeSet{TypeString}},
+					"parentID": {Type: TypeSet{TypeString}},
+					"name":     {Type: TypeSet{TypeString}},
+				},
+				AdditionalProperties: &False,
+				DependentRequired: map[string][]string{
+					"parentID": {"traceID"},
+				},
+			},
+		},
+		"omitzero embedded struct ptr": {
+			In: struct {
+				*Base  `json:",omitzero"`
+				*Trace `json:",omitempty"`
+			}{},
+			Out: &Schema{
+				Type: TypeSet{TypeObject},
+				Properties: map[string]Schema{
+					"id":       {Type: TypeSet{TypeString}},
+					"note":     {Type: TypeSet{TypeString}},
+					"traceID":  {Type: TypeSet{TypeString}},
+					"parentID": {Type: TypeSet{TypeString}},
+				},
+				AdditionalProperties: &False,
+				DependentRequired: map[string][]string{
+					"note":     {"id"},
+					"parentID": {"traceID"},
+				},
+			},
+		},
+		"nested embedded struct ptr": {
+			In: struct {
+				*Outer
+			}{},
+			Out: &Schema{
+				Type: TypeSet{TypeObject},
+				Properties: map[string]Schema{
+					"depth": {Type: TypeSet{TypeString}},
+					"level": {Type: TypeSet{TypeString}},
+				},
+				AdditionalProperties: &False,
+				DependentRequired: map[string][]string{
+					"depth": {"level"},
+				},
+			},
+		},
+		"embedded struct with name": {
+			In: struct {
+				*Base `json:"base,omitempty"`
+			}{},
+			Out: &Schema{
+				Type: TypeSet{TypeObject},
+				Properties: map[string]Schema{
+					"base": {OneOf: []Schema{
+						{Ref: "#/$defs/Base"},
+						{Type: TypeSet{TypeNull}},
+					}},
+				},
+				Defs: map[string]Schema{
+					"Base": {
+						Type: TypeSet{TypeObject},
+						Properties: map[string]Schema{
+							"id":   {Type: TypeSet{TypeString}},
+							"note": {Type: TypeSet{TypeString}},
+						},
+						AdditionalProperties: &False,
+						Required:             []string{"id"},
+					},
+				},
+				AdditionalProperties: &False,
+			},
+		},
+		"conflicting and ignored fields": {
+			In: struct {
+				Base
+				hidden
+				ID      int    `json:"id"`
+				Ignored string `json:"-"`
+				private string
+			}{},
+			Out: &Schema{
+				Type: TypeSet{TypeObject},
+				Properties: map[string]Schema{
+					"id":      {Type: TypeSet{TypeInteger}, Minimum: &intMin, Maximum: &intMax},
+					"note":    {Type: TypeSet{TypeString}},
+					"visible": {Type: TypeSet{TypeString}},
+				},
+				AdditionalProperties: &False,
+				Required:             []string{"visible", "id"},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			s, e := FromGoType(reflect.TypeOf(test.In))
+			if e != nil {
+				t.Errorf("unexpected error: %s", e)
+				return
+			}
+
+			if !reflect.DeepEqual(s, test.Out) {
+				t.Errorf("\nhave %s\nneed %s", s, test.Out)
+			}
+		})
+	}
+}
+
+func TestFromGoType_Interface(t *testing.T) {
+	tests := map[string]struct {
+		In   any
+		JSON string
+	}{
+		"map": {
+			In:   map[string]any{},
+			JSON: `{"additionalProperties":true,"type":["object"]}`,
+		},
+		"slice": {
+			In:   []any{},
+			JSON: `{"items":true,"type":["array"]}`,
+		},
+		"empty interface fields": {
+			In: struct {
+				Foo any `json:"foo"`
+				Bar any `json:"bar,omitempty"`
+			}{},
+			JSON: `{"properties":{"bar":true,"foo":true},"additionalProperties":false,"type":["object"],"required":["foo"]}`,
+		},
+		"raw message fields": {
+			In: struct {
+				Data    json.RawMessage  `json:"data"`
+				Payload json.RawMessage  `json:",omitempty"`
+				Extra   *json.RawMessage `json:"extra"`
+			}{},
+			JSON: `{"properties":{"Payload":true,"data":true,"extra":true},"additionalProperties":false,"type":["object"],"required":["data","extra"]}`,
+		},
+		"raw message": {
+			In:   json.RawMessage{},
+			JSON: `true`,
+		},
+		"raw messages in collections": {
+			In: struct {
+				List []json.RawMessage          `json:"list"`
+				Map  map[string]json.RawMessage `json:"map"`
+			}{},
+			JSON: `{"properties":{"list":{"items":true,"type":["array"]},"map":{"additionalProperties":true,"type":["object"]}},"additionalProperties":false,"type":["object"],"required":["list","map"]}`,
+		},
+		"number": {
+			In:   json.Number(""),
+			JSON: `{"type":["number"]}`,
+		},
+		"number fields": {
+			In: struct {
+				Amount json.Number            `json:"amount"`
+				Limit  *json.Number           `json:"limit"`
+				List   []json.Number          `json:"list"`
+				Map    map[string]json.Number `json:"map"`
+			}{},
+			JSON: `{"properties":{"amount":{"type":["number"]},"limit":{"type":["number","null"]},"list":{"items":{"type":["number"]},"type":["array"]},"map":{"additionalProperties":{"type":["number"]},"type":["object"]}},"additionalProperties":false,"type":["object"],"required":["amount","limit","list","map"]}`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			s, e := FromGoType(reflect.TypeOf(test.In))
+			if e != nil {
+				t.Errorf("unexpected error: %s", e)
+				return
+			}
+
+			if b, _ := json.Marshal(s); string(b) != test.JSON {
+				t.Errorf("\nhave %s\nneed %s", b, test.JSON)
+			}
+		})
+	}
+}
+
+func TestFromGoType_PropertyNames(t *testing.T) {
+	tests := map[string]struct {
+		In  any
+		Out *Schema
+		Err string
+	}{
+		"key constraints": {
+			In: struct {
+				Labels map[string]string `json:"labels" jsonschema:"keyPattern=^[a-z]+$,keyMinLength=1,keyMaxLength=63"`
+			}{},
+			Out: &Schema{
+				Type: TypeSet{TypeObject},
+				Properties: map[string]Schema{
+					"labels": {
+						Type:                 TypeSet{TypeObject},
+						AdditionalProperties: &Schema{Type: TypeSet{TypeString}},
+						PropertyNames: &Schema{
+							Pattern:   ptr("^[a-z]+$"),
+							MinLength: ptr(1),
+							MaxLength: ptr(63),
+						},
+					},
+				},
+				AdditionalProperties: &False,
+				Required:             []string{"labels"},
+			},
+		},
+		"escaped comma in pattern": {
+			In: struct {
+				Labels *map[string]int8 `json:"labels,omitempty" jsonschema:"keyPattern=^[a-z]{1\\,8}$"`
+			}{},
+			Out: &Schema{
+				Type: TypeSet{TypeObject},
+				Properties: map[string]Schema{
+					"labels": {
+						Type: TypeSet{TypeObject, TypeNull},
+						AdditionalProperties: &Schema{
+							Type:    TypeSet{TypeInteger},
+							Minimum: ptr(json.Number("-128")),
+							Maximum: ptr(json.Number("127")),
+						},
+						PropertyNames: &Schema{Pattern: ptr("^[a-z]{1,8}$")},
+					},
+				},
+				AdditionalProperties: &False,
+			},
+		},
+		"invalid pattern": {
+			In: struct {
+				Labels map[string]string `json:"labels" jsonschema:"keyPattern=^[a-z+$"`
+			}{},
+			Err: "schema.FromGoType: field labels: invalid option \"keyPattern\": error parsing regexp: missing closing ]: `[a-z+$`",
+		},
+		"invalid length": {
+			In: struct {
+				Labels map[string]string `json:"labels" jsonschema:"keyMinLength=-1"`
+			}{},
+			Err: `schema.FromGoType: field labels: invalid option "keyMinLength": negative length -1`,
+		},
+		"non-map field": {
+			In: struct {
+				Labels []string `json:"labels" jsonschema:"keyMinLength=1"`
+			}{},
+			Err: `schema.FromGoType: field labels: option "keyMinLength" requires a string keyed map`,
+		},
+		"unknown option": {
+			In: struct {
+				Labels map[string]string `json:"labels" jsonschema:"keyMinimum=1"`
+			}{},
+			Err: `schema.FromGoType: field labels: unknown option "keyMinimum"`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			s, e := FromGoType(reflect.TypeOf(test.In))
+			if test.Err != "" {
+				if e == nil || e.Error() != test.Err {
+					t.Errorf("\nhave error %v\nneed error %s", e, test.Err)
+				}
+				return
+			} else if e != nil {
+				t.Errorf("unexpected error: %s", e)
+				return
+			}
+
+			if !reflect.DeepEqual(s, test.Out) {
+				t.Errorf("\nhave %s\nneed %s", s, test.Out)
+			}
+		})
+	}
+}
+
+func TestFromGoType_NumericRange(t *testing.T) {
+	tests := map[string]struct {
+		In  any
+		Out map[string]Schema
+		Err string
+	}{
+		"bounds": {
+			In: struct {
+				Age   uint8       `json:"age" jsonschema:"minimum=1,maximum=130"`
+				Score *float64    `json:"score" jsonschema:"exclusiveMinimum=0,exclusiveMaximum=1.5e2"`
+				Total json.Number `json:"total" jsonschema:"minimum=-10"`
+			}{},
+			Out: map[string]Schema{
+				"age": {Type: TypeSet{TypeInteger}, Minimum: ptr(json.Number("1")), Maximum: ptr(json.Number("130"))},
+				"score": {
+					Type:             TypeSet{TypeNumber, TypeNull},
+					ExclusiveMinimum: ptr(json.Number("0")),
+					ExclusiveMaximum: ptr(json.Number("1.5e2")),
+				},
+				"total": {Type: TypeSet{TypeNumber}, Minimum: ptr(json.Number("-10"))},
+			},
+		},
+		"invalid number": {
+			In: struct {
+				Age int `json:"age" jsonschema:"minimum=ten"`
+			}{},
+			Err: `schema.FromGoType: field age: invalid option "minimum": invalid number "ten"`,
+		},
+		"trailing garbage": {
+			In: struct {
+				Age int `json:"age" jsonschema:"maximum=1 2"`
+			}{},
+			Err: `schema.FromGoType: field age: invalid option "maximum": invalid number "1 2"`,
+		},
+		"non-numeric field": {
+			In: struct {
+				Name string `json:"name" jsonschema:"minimum=1"`
+			}{},
+			Err: `schema.FromGoType: field name: option "minimum" requires a numeric type`,
+		},
+		"quoted numeric field": {
+			In: struct {
+				Age int `json:"age,string" jsonschema:"minimum=0"`
+			}{},
+			Err: `schema.FromGoType: field age: option "minimum" requires a numeric type`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			s, e := FromGoType(reflect.TypeOf(test.In))
+			if test.Err != "" {
+				if e == nil || e.Error() != test.Err {
+					t.Errorf("\nhave error %v\nneed error %s", e, test.Err)
+				}
+				return
+			} else if e != nil {
+				t.Errorf("unexpected error: %s", e)
+				return
+			}
+
+			if !reflect.DeepEqual(s.Properties, test.Out) {
+				t.Errorf("\nhave %v\nneed %v", s.Properties, test.Out)
+			}
+		})
+	}
+}
+
+func TestFromGoType_StringConstraints(t *testing.T) {
+	tests := map[string]struct {
+		In       any
+		Out      map[string]Schema
+		Required []string
+		Err      string
+	}{
+		"constraints": {
+			In: struct {
+				Name  string  `json:"name" jsonschema:"minLength=1,maxLength=64,pattern=^[a-z]+$"`
+				Nick  *string `json:"nick,omitempty" jsonschema:"maxLength=8"`
+				Count int     `json:"count,string" jsonschema:"pattern=^[1-9]$"`
+			}{},
+			Out: map[string]Schema{
+				"name":  {Type: TypeSet{TypeString}, MinLength: ptr(1), MaxLength: ptr(64), Pattern: ptr("^[a-z]+$")},
+				"nick":  {Type: TypeSet{TypeString, TypeNull}, MaxLength: ptr(8)},
+				"count": {Type: TypeSet{TypeString}, Pattern: ptr("^[1-9]$")},
+			},
+			Required: []string{"name", "count"},
+		},
+		"invalid pattern": {
+			In: struct {
+				Name string `json:"name" jsonschema:"pattern=(a"`
+			}{},
+			Err: "schema.FromGoType: field name: invalid option \"pattern\": error parsing regexp: missing closing ): `(a`",
+		},
+		"invalid length": {
+			In: struct {
+				Name string `json:"name" jsonschema:"minLength=x"`
+			}{},
+			Err: `schema.FromGoType: field name: invalid option "minLength": strconv.Atoi: parsing "x": invalid syntax`,
+		},
+		"non-string field": {
+			In: struct {
+				Count int `json:"count" jsonschema:"maxLength=1"`
+			}{},
+			Err: `schema.FromGoType: field count: option "maxLength" requires a string type`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			s, e := FromGoType(reflect.TypeOf(test.In))
+			if test.Err != "" {
+				if e == nil || e.Error() != test.Err {
+					t.Errorf("\nhave error %v\nneed error %s", e, test.Err)
+				}
+				return
+			} else if e != nil {
+				t.Errorf("unexpected error: %s", e)
+				return
+			}
+
+			if !reflect.DeepEqual(s.Properties, test.Out) {
+				t.Errorf("\nhave %v\nneed %v", s.Properties, test.Out)
+			}
+			if !slices.Equal(s.Required, test.Required) {
+				t.Errorf("unexpected required properties %q", s.Required)
+			}
+		})
+	}
+}
+
+func TestFromGoType_Metadata(t *testing.T) {
+	type Address struct {
+		Street string `json:"street"`
+	}
+
+	type Person struct {
+		Name string   `json:"name" jsonschema:"title=Name,description=The full name,examples=\"Ada\";\"Grace\""`
+		Age  int8     `json:"age" jsonschema:"examples=36\\,42"`
+		Home Address  `json:"home" jsonschema:"title=Home,description=Where the person lives"`
+		Work *Address `json:"work" jsonschema:"description=Where the person works"`
+	}
+
+	s, err := FromGoType(reflect.TypeOf(Person{}))
+	if err != nil {
+		t.Logf("unexpected error: %s", err)
+		t.FailNow()
+	}
+
+	person := s.Defs["Person"]
+	name, age, home, work := person.Properties["name"], person.Properties["age"], person.Properties["home"], person.Properties["work"]
+
+	if name.Title != "Name" || name.Description != "The full name" || !reflect.DeepEqual(name.Examples, []any{"Ada", "Grace"}) {
+		t.Errorf("unexpected schema of name %s", &name)
+	}
+	if !reflect.DeepEqual(age.Examples, []any{36.0, 42.0}) {
+		t.Errorf("unexpected schema of age %s", &age)
+	}
+	if home.Ref != "#/$defs/Address" || home.Title != "Home" || home.Description != "Where the person lives" {
+		t.Errorf("unexpected schema of home %s", &home)
+	}
+	if work.Description != "Where the person works" {
+		t.Errorf("unexpected schema of work %s", &work)
+	}
+
+	// The description of the first field is applied to the definition, the title
+	// is specific to the field.
+	if def := s.Defs["Address"]; def.Description != "Where the person lives" || def.Title != "" {
+		t.Errorf("unexpected definition %s", &def)
+	}
+
+	// Fields of the same type keep their own description next to the reference,
+	// the definition has the description of the first field.
+	type Route struct {
+		From Address `json:"from" jsonschema:"description=Where the route starts"`
+		To   Address `json:"to" jsonschema:"description=Where the route ends"`
+	}
+
+	if s, err = FromGoType(reflect.TypeOf(Route{})); err != nil {
+		t.Logf("unexpected error: %s", err)
+		t.FailNow()
+	}
+	route := s.Defs["Route"]
+	from, to := route.Properties["from"], route.Properties["to"]
+	if from.Ref != "#/$defs/Address" || from.Description != "Where the route starts" {
+		t.Errorf("unexpected schema of from %s", &from)
+	}
+	if to.Ref != "#/$defs/Address" || to.Description != "Where the route ends" {
+		t.Errorf("unexpected schema of to %s", &to)
+	}
+	if def := s.Defs["Address"]; def.Description != "Where the route starts" {
+		t.Errorf("unexpected definition %s", &def)
+	}
+
+	_, err = FromGoType(reflect.TypeOf(struct {
+		Name string `json:"name" jsonschema:"examples=Ada"`
+	}{}))
+	if expected := `schema.FromGoType: field name: invalid option "examples": invalid character 'A' looking for beginning of value`; err == nil || err.Error() != expected {
+		t.Errorf("\nhave %v\nneed %s", err, expected)
+	}
+}
+
+func TestFromGoType_Enum(t *testing.T) {
+	tests := map[string]struct {
+		In  any
+		Out map[string]Schema
+		Err string
+	}{
+		"typed values": {
+			In: struct {
+				Status   string      `json:"status" jsonschema:"enum=active,enum=inactive,enum=pending"`
+				Priority int8        `json:"priority" jsonschema:"enum=1,enum=-2"`
+				Retries  uint        `json:"retries" jsonschema:"enum=3"`
+				Ratio    float64     `json:"ratio" jsonschema:"enum=0.5"`
+				Enabled  bool        `json:"enabled" jsonschema:"enum=true"`
+				Amount   json.Number `json:"amount" jsonschema:"enum=1.50"`
+				Code     int         `json:"code,string" jsonschema:"enum=42"`
+			}{},
+			Out: map[string]Schema{
+				"status": {Type: TypeSet{TypeString}, Enum: []any{"active", "inactive", "pending"}},
+				"priority": {
+					Type:    TypeSet{TypeInteger},
+					Minimum: ptr(json.Number("-128")),
+					Maximum: ptr(json.Number("127")),
+					Enum:    []any{1, -2},
+				},
+				"retries": {
+					Type:    TypeSet{TypeInteger},
+					Minimum: ptr(json.Number("0")),
+					Maximum: ptr(json.Number(strconv.FormatUint(math.MaxUint64, 10))),
+					Enum:    []any{uint(3)},
+				},
+				"ratio":   {Type: TypeSet{TypeNumber}, Enum: []any{0.5}},
+				"enabled": {Type: TypeSet{TypeBoolean}, Enum: []any{true}},
+				"amount":  {Type: TypeSet{TypeNumber}, Enum: []any{json.Number("1.50")}},
+				"code":    {Type: TypeSet{TypeString}, Pattern: ptr(`^-?(0|[1-9][0-9]*)$`), Enum: []any{"42"}},
+			},
+		},
+		"pointers": {
+			In: struct {
+				Status *string `json:"status" jsonschema:"enum=active"`
+				Mode   *string `json:"mode,omitempty" jsonschema:"enum=fast"`
+			}{},
+			Out: map[string]Schema{
+				"status": {Type: TypeSet{TypeString, TypeNull}, Enum: []any{"active", nil}},
+				"mode":   {Type: TypeSet{TypeString, TypeNull}, Enum: []any{"fast"}},
+			},
+		},
+		"invalid value": {
+			In: struct {
+				Priority int8 `json:"priority" jsonschema:"enum=300"`
+			}{},
+			Err: `schema.FromGoType: field priority: invalid option "enum": strconv.ParseInt: parsing "300": value out of range`,
+		},
+		"unsupported type": {
+			In: struct {
+				Tags []string `json:"tags" jsonschema:"enum=a"`
+			}{},
+			Err: `schema.FromGoType: field tags: invalid option "enum": unsupported type []string`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			s, e := FromGoType(reflect.TypeOf(test.In))
+			if test.Err != "" {
+				if e == nil || e.Error() != test.Err {
+					t.Errorf("\nhave error %v\nneed error %s", e, test.Err)
+				}
+				return
+			} else if e != nil {
+				t.Errorf("unexpected error: %s", e)
+				return
+			}
+
+			if !reflect.DeepEqual(s.Properties, test.Out) {
+				t.Errorf("\nhave %v\nneed %v", s.Properties, test.Out)
+			}
+		})
+	}
+}
+
+func TestFromGoTypeWithConfig_OptionsTagKey(t *testing.T) {
+	typ := reflect.TypeOf(struct {
+		Age int `json:"age" schema:"minimum=0" jsonschema:"unrelated"`
+	}{})
+
+	s, err := FromGoTypeWithConfig(GoTypeConfig{OptionsTagKey: "schema"}, typ)
+	if err != nil {
+		t.Logf("unexpected error: %s", err)
+		t.FailNow()
+	}
+	if age := s.Properties["age"]; age.Minimum == nil || *age.Minimum != "0" {
+		t.Errorf("unexpected schema %s", &age)
+	}
+
+	// The default key is jsonschema.
+	if _, err = FromGoType(typ); err == nil || !strings.Contains(err.Error(), `unknown option "unrelated"`) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestFromGoType_Comment(t *testing.T) {
+	type Address struct {
+		Street string `json:"street" jsonschema:"comment=internal: do not expose"`
+	}
+
+	type Person struct {
+		Home Address  `json:"home" jsonschema:"comment=primary address"`
+		Work *Address `json:"work" jsonschema:"comment=secondary address"`
+	}
+
+	s, err := FromGoType(reflect.TypeOf(Person{}))
+	if err != nil {
+		t.Logf("unexpected error: %s", err)
+		t.FailNow()
+	}
+
+	expected := &Schema{
+		Ref: "#/$defs/Person",
+		Defs: map[string]Schema{
+			"Person": {
+				Type: TypeSet{TypeObject},
+				Properties: map[string]Schema{
+					"home": {Ref: "#/$defs/Address", Comment: "primary address"},
+					"work": {
+						Comment: "secondary address",
+						OneOf: []Schema{
+							{Ref: "#/$defs/Address"},
+							{Type: TypeSet{TypeNull}},
+						},
+					},
+				},
+				AdditionalProperties: &False,
+				Required:             []string{"home", "work"},
+			},
+			"Address": {
+				Comment: "primary address",
+				Type:    TypeSet{TypeObject},
+				Properties: map[string]Schema{
+					"street": {Type: TypeSet{TypeString}, Comment: "internal: do not expose"},
+				},
+				AdditionalProperties: &False,
+				Required:             []string{"street"},
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(s, expected) {
+		t.Errorf("\nhave %s\nneed %s", s, expected)
+	}
+}
+
+type Money struct {
+	cents int64
+}
+
+func (Money) JSONSchema() *Schema {
+	return &Schema{Type: TypeSet{TypeString}, Pattern: ptr(`^-?\d+\.\d{2}$`)}
+}
+
+type Color uint8
+
+var colorSchema = Schema{Enum: []any{"red", "green", "blue"}}
+
+func (*Color) JSONSchema() *Schema {
+	return &colorSchema
+}
+
+func TestFromGoType_SchemaProvider(t *testing.T) {
+	s, err := FromGoType(reflect.TypeOf(struct {
+		Price    Money    `json:"price"`
+		Discount *Money   `json:"discount,omitempty"`
+		Color    Color    `json:"color"`
+		Colors   []*Color `json:"colors"`
+	}{}))
+	if err != nil {
+		t.Logf("unexpected error: %s", err)
+		t.FailNow()
+	}
+
+	expected := &Schema{
+		Type: TypeSet{TypeObject},
+		Properties: map[string]Schema{
+			"price": {Ref: "#/$defs/Money"},
+			"discount": {OneOf: []Schema{
+				{Ref: "#/$defs/Money"},
+				{Type: TypeSet{TypeNull}},
+			}},
+			"color": {Ref: "#/$defs/Color"},
+			"colors": {
+				Type: TypeSet{TypeArray},
+				Items: &Schema{OneOf: []Schema{
+					{Ref: "#/$defs/Color"},
+					{Type: TypeSet{TypeNull}},
+				}},
+			},
+		},
+		Defs: map[string]Schema{
+			"Money": {Type: TypeSet{TypeString}, Pattern: ptr(`^-?\d+\.\d{2}$`)},
+			"Color": {Enum: []any{"red", "green", "blue"}},
+		},
+		AdditionalProperties: &False,
+		Required:             []string{"price", "color", "colors"},
+	}
+
+	if !reflect.DeepEqual(s, expected) {
+		t.Errorf("\nhave %s\nneed %s", s, expected)
+	}
+
+	s.Defs["Color"].Enum[0] = "yellow"
+	if colorSchema.Enum[0] != "red" {
+		t.Errorf("provided schema was modified")
+	}
+}
+
+func TestFromGoType_SchemaProviderInline(t *testing.T) {
+	s, err := FromGoType(reflect.TypeOf(struct {
+		Color  Color   `json:"color"`
+		Accent *Color  `json:"accent" jsonschema:"inline"`
+		Shade  Color   `json:"shade" jsonschema:"inline"`
+		Status *Status `json:"status"`
+	}{}))
+	if err != nil {
+		t.Logf("unexpected error: %s", err)
+		t.FailNow()
+	}
+
+	colors := Schema{Enum: []any{"red", "green", "blue"}}
+	expected := &Schema{
+		Type: TypeSet{TypeObject},
+		Properties: map[string]Schema{
+			"color":  {Ref: "#/$defs/Color"},
+			"accent": {OneOf: []Schema{colors, {Type: TypeSet{TypeNull}}}},
+			"shade":  colors,
+			"status": {OneOf: []Schema{
+				{Ref: "#/$defs/Status"},
+				{Type: TypeSet{TypeNull}},
+			}},
+		},
+		Defs: map[string]Schema{
+			"Color":  colors,
+			"Status": *Status("").JSONSchema(),
+		},
+		AdditionalProperties: &False,
+		Required:             []string{"color", "accent", "shade", "status"},
+	}
+
+	if !reflect.DeepEqual(s, expected) {
+		t.Errorf("\nhave %s\nneed %s", s, expected)
+	}
+}
+
+type Status string
+
+func (Status) JSONSchema() *Schema {
+	return &Schema{Type: TypeSet{TypeString}, Enum: []any{"active", "inactive"}}
+}
+
+type StatusDetail struct {
+	Since string `json:"since"`
+}
+
+type region string
+
+func TestFromGoType_EnumKeyedMap(t *testing.T) {
+	s, err := FromGoType(reflect.TypeOf(struct {
+		Statuses map[Status]StatusDetail `json:"statuses"`
+		Regions  map[region]int8         `json:"regions"`
+	}{}))
+	if err != nil {
+		t.Logf("unexpected error: %s", err)
+		t.FailNow()
+	}
+
+	expected := map[string]Schema{
+		"statuses": {
+			Type:                 TypeSet{TypeObject},
+			PropertyNames:        &Schema{Ref: "#/$defs/Status"},
+			AdditionalProperties: &Schema{Ref: "#/$defs/StatusDetail"},
+		},
+		"regions": {
+			Type:                 TypeSet{TypeObject},
+			AdditionalProperties: &Schema{Type: TypeSet{TypeInteger}, Minimum: ptr(json.Number("-128")), Maximum: ptr(json.Number("127"))},
+		},
+	}
+	if !reflect.DeepEqual(s.Properties, expected) {
+		t.Errorf("\nhave %v\nneed %v", s.Properties, expected)
+	}
+
+	if def := s.Defs["Status"]; !reflect.DeepEqual(def.Enum, []any{"active", "inactive"}) {
+		t.Errorf("unexpected definition of Status: %s", &def)
+	}
+}
+
+func TestFromGoTypeWithConfig_PostProcess(t *testing.T) {
+	type Item struct {
+		Name string `json:"name"`
+	}
+	typ := reflect.TypeOf(struct {
+		Items []Item `json:"items"`
+	}{})
+
+	var calls []int
+	config := GoTypeConfig{PostProcess: []func(*Schema) error{
+		func(s *Schema) error {
+			calls = append(calls, 0)
+			return RenameDef(s, "Item", "LineItem")
+		},
+		func(s *Schema) error {
+			calls = append(calls, 1)
+			if _, ok := s.Defs["LineItem"]; !ok {
+				return errors.New("definition not renamed")
+			}
+			return nil
+		},
+	}}
+
+	s, err := FromGoTypeWithConfig(config, typ)
+	if err != nil {
+		t.Logf("unexpected error: %s", err)
+		t.FailNow()
+	}
+	if ref := s.Properties["items"].Items.Ref; ref != "#/$defs/LineItem" || !slices.Equal(calls, []int{0, 1}) {
+		t.Errorf("unexpected result: ref %q, calls %v", ref, calls)
+	}
+
+	calls = nil
+	config.PostProcess[0] = func(s *Schema) error {
+		calls = append(calls, 0)
+		return errors.New("failed")
+	}
+	_, err = FromGoTypeWithConfig(config, typ)
+	if expected := "schema.FromGoType: post-processor 0: failed"; err == nil || err.Error() != expected {
+		t.Errorf("\nhave %v\nneed %s", err, expected)
+	}
+	if !slices.Equal(calls, []int{0}) {
+		t.Errorf("expected processing to stop, have calls %v", calls)
+	}
+}
+
+func TestFromGoType_StringOption(t *testing.T) {
+	s, err := FromGoType(reflect.TypeOf(struct {
+		K     *uint8  `json:",omitempty,string"`
+		L     *uint8  `json:",string,omitempty"`
+		Int   int     `json:"int,string"`
+		Float float64 `json:"float,string"`
+		Bool  bool    `json:"bool,string"`
+		Str   string  `json:"str,string"`
+		Money Money   `json:"money,string"`
+		Slice []int   `json:"slice,string"`
+	}{}))
+	if err != nil {
+		t.Logf("unexpected error: %s", err)
+		t.FailNow()
+	}
+
+	uintPattern := ptr(`^(0|[1-9][0-9]*)$`)
+	expected := map[string]Schema{
+		"K":     {Type: TypeSet{TypeString, TypeNull}, Pattern: uintPattern},
+		"L":     {Type: TypeSet{TypeString, TypeNull}, Pattern: uintPattern},
+		"int":   {Type: TypeSet{TypeString}, Pattern: ptr(`^-?(0|[1-9][0-9]*)$`)},
+		"float": {Type: TypeSet{TypeString}, Pattern: ptr(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][-+]?[0-9]+)?$`)},
+		"bool":  {Type: TypeSet{TypeString}, Enum: []any{"true", "false"}},
+		"str":   {Type: TypeSet{TypeString}},
+		"money": {Ref: "#/$defs/Money"},
+		"slice": {Type: TypeSet{TypeArray}, Items: &Schema{Type: TypeSet{TypeInteger}, Minimum: ptr(json.Number(strconv.FormatInt(math.MinInt64, 10))), Maximum: ptr(json.Number(strconv.FormatInt(math.MaxInt64, 10)))}},
+	}
+	if !reflect.DeepEqual(s.Properties, expected) {
+		t.Errorf("\nhave %v\nneed %v", s.Properties, expected)
+	}
+
+	if !slices.Equal(s.Required, []string{"int", "float", "bool", "str", "money", "slice"}) {
+		t.Errorf("unexpected required properties %q", s.Required)
+	}
+}
+
+func TestFromGoTypeWithConfig_NameFunc(t *testing.T) {
+	typ := reflect.TypeOf(struct {
+		Token   token.Position   `json:"token"`
+		Scanner scanner.Position `json:"scanner"`
+	}{})
+
+	if _, err := FromGoType(typ); err == nil {
+		t.Errorf("expected error for types sharing a name")
+	}
+
+	config := GoTypeConfig{NameFunc: func(t reflect.Type) string {
+		return path.Base(t.PkgPath()) + t.Name()
+	}}
+	s, err := FromGoTypeWithConfig(config, typ)
+	if err != nil {
+		t.Logf("unexpected error: %s", err)
+		t.FailNow()
+	}
+
+	if ref := s.Properties["token"].Ref; ref != "#/$defs/tokenPosition" {
+		t.Errorf("unexpected reference %q", ref)
+	}
+	if ref := s.Properties["scanner"].Ref; ref != "#/$defs/scannerPosition" {
+		t.Errorf("unexpected reference %q", ref)
+	}
+	if names := sortedNames(s.Defs); !slices.Equal(names, []string{"scannerPosition", "tokenPosition"}) {
+		t.Errorf("have definitions %v", names)
+	}
+}
+
+func TestQualifiedName(t *testing.T) {
+	tests := map[reflect.Type]string{
+		reflect.TypeOf(token.Position{}):   "goTokenPosition",
+		reflect.TypeOf(scanner.Position{}): "textScannerPosition",
+		reflect.TypeOf(json.Number("")):    "encodingJsonNumber",
+		reflect.TypeOf(Address{}):          "jsonschemaTestAddress",
+		reflect.TypeOf(0):                  "int",
+	}
+
+	for typ, expected := range tests {
+		if name := QualifiedName(typ); name != expected {
+			t.Errorf("%s: have %q, need %q", typ, name, expected)
+		}
+	}
+
+	s, err := FromGoTypeWithConfig(GoTypeConfig{NameFunc: QualifiedName}, reflect.TypeOf(struct {
+		Token   token.Position   `json:"token"`
+		Scanner scanner.Position `json:"scanner"`
+	}{}))
+	if err != nil {
+		t.Logf("unexpected error: %s", err)
+		t.FailNow()
+	}
+
+	for prop, name := range map[string]string{"token": "goTokenPosition", "scanner": "textScannerPosition"} {
+		ref := s.Properties[prop].Ref
+		if ref != "#/$defs/"+name {
+			t.Errorf("%s: unexpected reference %q", prop, ref)
+		}
+		if _, err = ResolveReference(ResolveConfig{}, ref, s); err != nil {
+			t.Errorf("%s: %s", prop, err)
+		}
+	}
+}
+
+type Address struct {
+	Street string `json:"street"`
+}
+
+type shippingAddress = Address
+
+func TestFromGoType_DefinitionCollision(t *testing.T) {
+	type Address struct {
+		City string `json:"city"`
+	}
+
+	_, err := FromGoType(reflect.TypeOf(struct {
+		Billing  Address         `json:"billing"`
+		Shipping shippingAddress `json:"shipping"`
+	}{}))
+	if err == nil || !strings.HasSuffix(err.Error(), `: different types share the definition "Address"`) {
+		t.Logf("expected an error for colliding definitions, got %v", err)
+		t.FailNow()
+	}
+}
+
+// TestFromGoType_DefinitionAlias ensures an alias is not reported as a colliding
+// definition, since it is the same type as the type it denotes.
+func TestFromGoType_DefinitionAlias(t *testing.T) {
+	for i, v := range []any{
+		struct {
+			Shipping shippingAddress `json:"shipping"`
+			Return   shippingAddress `json:"return"`
+		}{},
+		struct {
+			Billing  Address         `json:"billing"`
+			Shipping shippingAddress `json:"shipping"`
+		}{},
+	} {
+		s, err := FromGoType(reflect.TypeOf(v))
+		if err != nil {
+			t.Errorf("test #%d: unexpected error: %s", i, err)
+			continue
+		}
+		if _, ok := s.Defs["Address"]; !ok || len(s.Defs) != 1 {
+			t.Errorf("test #%d: have $defs %s", i, s)
+		}
+	}
+}
+
+type Order struct {
+	Lines []OrderLine `json:"lines"`
+}
+
+type OrderLine struct {
+	Product map[string]Product `json:"product"`
+}
+
+type Product struct {
+	Name string `json:"name"`
+}
+
+func TestFromGoType_TransitiveDefinitions(t *testing.T) {
+	s, err := FromGoType(reflect.TypeOf(struct {
+		Order Order `json:"order"`
+	}{}))
+	if err != nil {
+		t.Logf("unexpected error: %s", err)
+		t.FailNow()
+	}
+
+	expected := map[string]Schema{
+		"Order": {
+			Type: TypeSet{TypeObject},
+			Properties: map[string]Schema{
+				"lines": {Type: TypeSet{TypeArray}, Items: &Schema{Ref: "#/$defs/OrderLine"}},
+			},
+			AdditionalProperties: &False,
+			Required:             []string{"lines"},
+		},
+		"OrderLine": {
+			Type: TypeSet{TypeObject},
+			Properties: map[string]Schema{
+				"product": {Type: TypeSet{TypeObject}, AdditionalProperties: &Schema{Ref: "#/$defs/Product"}},
+			},
+			AdditionalProperties: &False,
+			Required:             []string{"product"},
+		},
+		"Product": {
+			Type: TypeSet{TypeObject},
+			Properties: map[string]Schema{
+				"name": {Type: TypeSet{TypeString}},
+			},
+			AdditionalProperties: &False,
+			Required:             []string{"name"},
+		},
+	}
+
+	if !reflect.DeepEqual(s.Defs, expected) {
+		t.Errorf("\nhave %v\nneed %v", s.Defs, expected)
+	}
+}
+
+func TestFromGoTypeWithConfig_DefinitionBaseID(t *testing.T) {
+	config := GoTypeConfig{DefinitionBaseID: "https://example.com/schemas/"}
+	s, err := FromGoTypeWithConfig(config, reflect.TypeOf(Order{}))
+	if err != nil {
+		t.Logf("unexpected error: %s", err)
+		t.FailNow()
+	}
+
+	if s.Ref != "https://example.com/schemas/defs/Order" {
+		t.Errorf("have %q, need %q", s.Ref, "https://example.com/schemas/defs/Order")
+	}
+
+	for _, name := range []string{"Order", "OrderLine", "Product"} {
+		if id, need := s.Defs[name].ID, "https://example.com/schemas/defs/"+name; id != need {
+			t.Errorf("$defs/%s: have $id %q, need %q", name, id, need)
+		}
+	}
+
+	// The references between definitions are resolved against their own $id.
+	line := s.Defs["Order"].Properties["lines"].Items
+	if line == nil || line.Ref != "https://example.com/schemas/defs/OrderLine" {
+		t.Logf("unexpected items of lines: %v", line)
+		t.FailNow()
+	}
+
+	resolved, err := ResolveReference(ResolveConfig{}, line.Ref, s)
+	if err != nil {
+		t.Logf("unexpected error: %s", err)
+		t.FailNow()
+	}
+	if expected := s.Defs["OrderLine"]; !reflect.DeepEqual(*resolved, expected) {
+		t.Errorf("\nhave %s\nneed %s", resolved, &expected)
+	}
+}
+
+func TestFromGoTypeWithConfig_DialectAndBaseID(t *testing.T) {
+	config := GoTypeConfig{Dialect: Draft2020Schema, BaseID: "https://example.com/product.json"}
+	s, err := FromGoTypeWithConfig(config, reflect.TypeOf(Product{}))
+	if err != nil {
+		t.Logf("unexpected error: %s", err)
+		t.FailNow()
+	}
+
+	root := *s
+	root.Defs = nil
+	expected := &Schema{
+		Schema: string(Draft2020Schema),
+		ID:     "https://example.com/product.json",
+		Ref:    "#/$defs/Product",
+	}
+	if !reflect.DeepEqual(&root, expected) {
+		t.Errorf("\nhave %s\nneed %s", &root, expected)
+	}
+
+	if def := s.Defs["Product"]; def.Schema != "" || def.ID != "" {
+		t.Errorf("unexpected $schema %q and $id %q of the definition", def.Schema, def.ID)
+	}
+
+	s, err = FromGoTypeWithConfig(config, reflect.TypeOf([]string{}))
+	if err != nil {
+		t.Logf("unexpected error: %s", err)
+		t.FailNow()
+	}
+	if s.Schema != string(Draft2020Schema) || s.ID != config.BaseID || s.Defs != nil {
+		t.Errorf("unexpected root %s", s)
+	}
+}
+
+func TestFromGoType_Inline(t *testing.T) {
+	type Money struct {
+		Amount   int    `json:"amount"`
+		Currency string `json:"currency"`
+	}
+	type Invoice struct {
+		Total Money  `json:"total"`
+		Tax   *Money `json:"tax" jsonschema:"inline"`
+	}
+
+	s, err := FromGoType(reflect.TypeOf(Invoice{}))
+	if err != nil {
+		t.Logf("unexpected error: %s", err)
+		t.FailNow()
+	}
+
+	money := s.Defs["Money"]
+	if total := s.Defs["Invoice"].Properties["total"]; total.Ref != "#/$defs/Money" {
+		t.Errorf("expected reference to Money, have %s", &total)
+	}
+
+	tax := s.Defs["Invoice"].Properties["tax"]
+	expected := Copy(money)
+	expected.Type = TypeSet{TypeObject, TypeNull}
+	if !reflect.DeepEqual(tax, expected) {
+		t.Errorf("\nhave %s\nneed %s", &tax, &expected)
+	}
+}
+
+func TestFromGoType_InlineRecursive(t *testing.T) {
+	type Node struct {
+		Value string `json:"value"`
+		Next  *Node  `json:"next" jsonschema:"inline"`
+	}
+	type List struct {
+		Head Node `json:"head" jsonschema:"inline"`
+	}
+
+	s, err := FromGoType(reflect.TypeOf(List{}))
+	if err != nil {
+		t.Logf("unexpected error: %s", err)
+		t.FailNow()
+	}
+
+	// The type is not inlined into itself, the recursion uses the definition.
+	head := s.Defs["List"].Properties["head"]
+	if head.Ref != "" || head.Properties["value"].Type[0] != TypeString {
+		t.Errorf("expected inlined Node, have %s", &head)
+	}
+	node, ok := s.Defs["Node"]
+	if !ok {
+		t.Logf("expected definition of Node")
+		t.FailNow()
+	}
+	if next := node.Properties["next"]; len(next.OneOf) != 2 || next.OneOf[0].Ref != "#/$defs/Node" {
+		t.Errorf("expected recursive reference, have %s", &next)
+	}
+}
+
+func TestFromGoTypeWithConfig_InlineDefinitions(t *testing.T) {
+	config := GoTypeConfig{InlineDefinitions: true}
+
+	t.Run("Order", func(t *testing.T) {
+		s, err := FromGoTypeWithConfig(config, reflect.TypeOf(Order{}))
+		if err != nil {
+			t.Logf("unexpected error: %s", err)
+			t.FailNow()
+		}
+
+		product := Schema{
+			Type:                 TypeSet{TypeObject},
+			Properties:           map[string]Schema{"name": {Type: TypeSet{TypeString}}},
+			AdditionalProperties: &False,
+			Required:             []string{"name"},
+		}
+		line := Schema{
+			Type: TypeSet{TypeObject},
+			Properties: map[string]Schema{"product": {
+				Type:                 TypeSet{TypeObject},
+				AdditionalProperties: &product,
+			}},
+			AdditionalProperties: &False,
+			Required:             []string{"product"},
+		}
+		expected := &Schema{
+			Type: TypeSet{TypeObject},
+			Properties: map[string]Schema{"lines": {
+				Type:  TypeSet{TypeArray},
+				Items: &line,
+			}},
+			AdditionalProperties: &False,
+			Required:             []string{"lines"},
+		}
+
+		if !reflect.DeepEqual(s, expected) {
+			t.Errorf("\nhave %s\nneed %s", s, expected)
+		}
+	})
+
+	t.Run("Employee", func(t *testing.T) {
+		s, err := FromGoTypeWithConfig(config, reflect.TypeOf(Employee{}))
+		if err != nil {
+			t.Logf("unexpected error: %s", err)
+			t.FailNow()
+		}
+
+		// Both types recur, so both are defined, while the root is still inlined.
+		if s.Ref != "" || len(s.Defs) != 2 {
+			t.Errorf("expected inlined root and two definitions, have %s", s)
+		}
+		if manager := s.Properties["manager"]; len(manager.OneOf) != 2 || manager.OneOf[0].Ref != "#/$defs/Employee" {
+			t.Errorf("expected recursive reference, have %s", &manager)
+		}
+		if head := s.Defs["Department"].Properties["head"]; head.Ref != "#/$defs/Employee" {
+			t.Errorf("expected recursive reference, have %s", &head)
+		}
+
+		if _, err = Compile(context.Background(), s, nil); err != nil {
+			t.Errorf("unexpected error: %s", err)
+		}
+	})
+}
+
+// Employee and Department reference each other.
+type Employee struct {
+	Department *Department `json:"department"`
+	Manager    *Employee   `json:"manager"`
+}
+
+type Department struct {
+	Head      Employee   `json:"head"`
+	Employees []Employee `json:"employees"`
+}
+
+func TestFromGoType_MutualRecursion(t *testing.T) {
+	for _, typ := range []reflect.Type{reflect.TypeOf(Employee{}), reflect.TypeOf(Department{})} {
+		t.Run(typ.Name(), func(t *testing.T) {
+			s, err := FromGoType(typ)
+			if err != nil {
+				t.Logf("unexpected error: %s", err)
+				t.FailNow()
+			}
+
+			expected := map[string]Schema{
+				"Employee": {
+					Type: TypeSet{TypeObject},
+					Properties: map[string]Schema{
+						"department": {OneOf: []Schema{{Ref: "#/$defs/Department"}, {Type: TypeSet{TypeNull}}}},
+						"manager":    {OneOf: []Schema{{Ref: "#/$defs/Employee"}, {Type: TypeSet{TypeNull}}}},
+					},
+					AdditionalProperties: &False,
+					Required:             []string{"department", "manager"},
+				},
+				"Department": {
+					Type: TypeSet{TypeObject},
+					Properties: map[string]Schema{
+						"head":      {Ref: "#/$defs/Employee"},
+						"employees": {Type: TypeSet{TypeArray}, Items: &Schema{Ref: "#/$defs/Employee"}},
+					},
+					AdditionalProperties: &False,
+					Required:             []string{"head", "employees"},
+				},
+			}
+
+			if s.Ref != "#/$defs/"+typ.Name() {
+				t.Errorf("have $ref %q, need %q", s.Ref, "#/$defs/"+typ.Name())
+			}
+			if !reflect.DeepEqual(s.Defs, expected) {
+				t.Errorf("\nhave %v\nneed %v", s.Defs, expected)
+			}
+		})
+	}
+}
+
+func TestFromGoType_InlineInvalid(t *testing.T) {
+	_, err := FromGoType(reflect.TypeOf(struct {
+		Tags []string `json:"tags" jsonschema:"inline"`
+	}{}))
+	if err == nil {
+		t.Errorf("expected error")
+	}
+}
+
+// UUID is encoded as a string by a custom MarshalText.
+type UUID [16]byte
+
+func TestFromGoTypeWithConfig_Formats(t *testing.T) {
+	config := GoTypeConfig{Formats: map[reflect.Type]FormatSpec{
+		reflect.TypeOf(UUID{}):      {Type: TypeString, Format: "uuid"},
+		reflect.TypeOf(time.Time{}): {Type: TypeString, Format: "date-time"},
+		reflect.TypeOf([]int{}):     {Type: TypeString, Pattern: "^[0-9,]*$"},
+	}}
+
+	s, err := FromGoTypeWithConfig(config, reflect.TypeOf(struct {
+		ID      UUID      `json:"id"`
+		Parent  *UUID     `json:"parent"`
+		Created time.Time `json:"created"`
+		List    []int     `json:"list"`
+	}{}))
+	if err != nil {
+		t.Logf("unexpected error: %s", err)
+		t.FailNow()
+	}
+
+	expected := &Schema{
+		Type: TypeSet{TypeObject},
+		Properties: map[string]Schema{
+			"id":      {Ref: "#/$defs/UUID"},
+			"parent":  {OneOf: []Schema{{Ref: "#/$defs/UUID"}, {Type: TypeSet{TypeNull}}}},
+			"created": {Ref: "#/$defs/Time"},
+			"list":    {Type: TypeSet{TypeString}, Pattern: ptr("^[0-9,]*$")},
+		},
+		AdditionalProperties: &False,
+		Required:             []string{"id", "parent", "created", "list"},
+		Defs: map[string]Schema{
+			"UUID": {Type: TypeSet{TypeString}, Format: ptr("uuid")},
+			"Time": {Type: TypeSet{TypeString}, Format: ptr("date-time")},
+		},
+	}
+	if !reflect.DeepEqual(s, expected) {
+		t.Errorf("\nhave %s\nneed %s", s, expected)
+	}
+}
+
+// Timestamp is encoded like the embedded time.Time.
+type Timestamp struct {
+	time.Time
+}
+
+// LocalTime has no methods, it is encoded as an empty object.
+type LocalTime time.Time
+
+func TestFromGoType_Time(t *testing.T) {
+	s, err := FromGoType(reflect.TypeOf(struct {
+		Created time.Time   `json:"created"`
+		Deleted *time.Time  `json:"deleted"`
+		History []time.Time `json:"history"`
+		Updated Timestamp   `json:"updated"`
+		Local   LocalTime   `json:"local"`
+	}{}))
+	if err != nil {
+		t.Logf("unexpected error: %s", err)
+		t.FailNow()
+	}
+
+	dateTime := Schema{Type: TypeSet{TypeString}, Format: ptr("date-time")}
+	expected := &Schema{
+		Type: TypeSet{TypeObject},
+		Properties: map[string]Schema{
+			"created": dateTime,
+			"deleted": {Type: TypeSet{TypeString, TypeNull}, Format: ptr("date-time")},
+			"history": {Type: TypeSet{TypeArray}, Items: &dateTime},
+			"updated": {Ref: "#/$defs/Timestamp"},
+			"local":   {Ref: "#/$defs/LocalTime"},
+		},
+		AdditionalProperties: &False,
+		Required:             []string{"created", "deleted", "history", "updated", "local"},
+		Defs: map[string]Schema{
+			"Timestamp": dateTime,
+			"LocalTime": {Type: TypeSet{TypeObject}, Properties: map[string]Schema{}, AdditionalProperties: &False},
+		},
+	}
+	if !reflect.DeepEqual(s, expected) {
+		t.Errorf("\nhave %s\nneed %s", s, expected)
+	}
+}
+
+func TestFromGoTypeWithConfig_TagKey(t *testing.T) {
+	type Server struct {
+		Host    string `json:"host" yaml:"hostname"`
+		Port    int    `json:"port" yaml:"port,omitempty"`
+		Secret  string `json:"secret" yaml:"-"`
+		Verbose bool
+	}
+
+	s, err := FromGoTypeWithConfig(GoTypeConfig{TagKey: "yaml"}, reflect.TypeOf(Server{}))
+	if err != nil {
+		t.Logf("unexpected error: %s", err)
+		t.FailNow()
+	}
+
+	have := s.Defs["Server"]
+	if names := sortedNames(have.Properties); !slices.Equal(names, []string{"Verbose", "hostname", "port"}) {
+		t.Errorf("have properties %v", names)
+	}
+	if !slices.Equal(have.Required, []string{"hostname", "Verbose"}) {
+		t.Errorf("have required %v", have.Required)
+	}
+
+	// The fields of a type are cached per tag key.
+	s, _ = FromGoType(reflect.TypeOf(Server{}))
+	have = s.Defs["Server"]
+	if names := sortedNames(have.Properties); !slices.Equal(names, []string{"Verbose", "host", "port", "secret"}) {
+		t.Errorf("have properties %v", names)
+	}
+}
+
+func sortedNames(m map[string]Schema) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func TestFromGoTypeWithConfig_IncludeField(t *testing.T) {
+	type Meta struct {
+		Trace string `json:"trace" versions:"v2"`
+	}
+	type Request struct {
+		*Meta
+		Name  string `json:"name"`
+		Email string `json:"email" versions:"v2,v3"`
+		Phone string `json:"phone" versions:"v3"`
+	}
+
+	config := GoTypeConfig{
+		IncludeField: func(field FieldInfo) bool {
+			versions, ok := field.Field.Tag.Lookup("versions")
+			return !ok || slices.Contains(strings.Split(versions, ","), "v2")
+		},
+	}
+
+	s, err := FromGoTypeWithConfig(config, reflect.TypeOf(Request{}))
+	if err != nil {
+		t.Logf("unexpected error: %s", err)
+		t.FailNow()
+	}
+
+	expected := Schema{
+		Type: TypeSet{TypeObject},
+		Properties: map[string]Schema{
+			"trace": {Type: TypeSet{TypeString}},
+			"name":  {Type: TypeSet{TypeString}},
+			"email": {Type: TypeSet{TypeString}},
+		},
+		AdditionalProperties: &False,
+		Required:             []string{"name", "email"},
+	}
+
+	if have := s.Defs["Request"]; !reflect.DeepEqual(have, expected) {
+		t.Errorf("\nhave %s\nneed %s", &have, &expected)
+	}
+}
+
+// BillingAddress is a defined type with the same underlying type as Address, but
+// unlike the alias shippingAddress it is a distinct type with its own name.
+type BillingAddress Address
+
+func TestFromGoType_AliasAndDefinedType(t *testing.T) {
+	s, err := FromGoType(reflect.TypeOf(struct {
+		Home     Address         `json:"home"`
+		Shipping shippingAddress `json:"shipping"`
+		Billing  BillingAddress  `json:"billing"`
+	}{}))
+	if err != nil {
+		t.Logf("unexpected error: %s", err)
+		t.FailNow()
+	}
+
+	address := Schema{
+		Type: TypeSet{TypeObject},
+		Properties: map[string]Schema{
+			"street": {Type: TypeSet{TypeString}},
+		},
+		AdditionalProperties: &False,
+		Required:             []string{"street"},
+	}
+
+	expected := &Schema{
+		Type: TypeSet{TypeObject},
+		Properties: map[string]Schema{
+			"home":     {Ref: "#/$defs/Address"},
+			"shipping": {Ref: "#/$defs/Address"},
+			"billing":  {Ref: "#/$defs/BillingAddress"},
+		},
+		Defs: map[string]Schema{
+			"Address":        address,
+			"BillingAddress": Copy(address),
+		},
+		AdditionalProperties: &False,
+		Required:             []string{"home", "shipping", "billing"},
+	}
+
+	if !reflect.DeepEqual(s, expected) {
+		t.Errorf("\nhave %s\nneed %s", s, expected)
+	}
+}
+
+func TestFromGoType_AdditionalProperties(t *testing.T) {
+	type Labels struct {
+		Name  string            `json:"name"`
+		Extra map[string]string `json:"-" jsonschema:"additionalProperties"`
+	}
+
+	s, err := FromGoType(reflect.TypeOf(struct {
+		Labels Labels `json:"labels"`
+		Tags   struct {
+			Extra *map[string]int `json:"extra,omitempty" jsonschema:"additionalProperties"`
+		} `json:"tags"`
+	}{}))
+	if err != nil {
+		t.Logf("unexpected error: %s", err)
+		t.FailNow()
+	}
+
+	labels := Schema{
+		Type: TypeSet{TypeObject},
+		Properties: map[string]Schema{
+			"name": {Type: TypeSet{TypeString}},
+		},
+		AdditionalProperties: &Schema{Type: TypeSet{TypeString}},
+		Required:             []string{"name"},
+	}
+	if have := s.Defs["Labels"]; !reflect.DeepEqual(have, labels) {
+		t.Errorf("\nhave %s\nneed %s", &have, &labels)
+	}
+
+	tags := s.Properties["tags"]
+	if len(tags.Properties) != 0 || !reflect.DeepEqual(tags.AdditionalProperties.Type, TypeSet{TypeInteger}) {
+		t.Errorf("unexpected schema %s", &tags)
+	}
+
+	errTests := map[reflect.Type]string{
+		reflect.TypeOf(struct {
+			Extra []string `json:"-" jsonschema:"additionalProperties"`
+		}{}): `schema.FromGoType: field Extra: option "additionalProperties" requires a string keyed map`,
+		reflect.TypeOf(struct {
+			A map[string]int `json:"a" jsonschema:"additionalProperties"`
+			B map[string]int `json:"b" jsonschema:"additionalProperties"`
+		}{}): `schema.FromGoType: fields a and b both capture additional properties`,
+	}
+	for typ, msg := range errTests {
+		if _, err := FromGoType(typ); err == nil || err.Error() != msg {
+			t.Errorf("expected error %q, got %v", msg, err)
+		}
+	}
+}
+
+func TestFromGoTypeWithConfig_ExplicitNull(t *testing.T) {
+	typ := reflect.TypeOf(struct {
+		Name    *string           `json:"name"`
+		Tags    *[]string         `json:"tags"`
+		Address *Address          `json:"address"`
+		Price   *NullableMoney    `json:"price"`
+		Extra   *map[string]int64 `json:"extra"`
+		Any     *any              `json:"any"`
+	}{})
+
+	null := Schema{Type: TypeSet{TypeNull}}
+	int64Schema, _ := FromGoType(reflect.TypeOf(int64(0)))
+
+	tests := []struct {
+		config     GoTypeConfig
+		properties map[string]Schema
+	}{
+		{
+			config: GoTypeConfig{},
+			properties: map[string]Schema{
+				"name":    {Type: TypeSet{TypeString, TypeNull}},
+				"tags":    {Type: TypeSet{TypeArray, TypeNull}, Items: &Schema{Type: TypeSet{TypeString}}},
+				"address": {OneOf: []Schema{{Ref: "#/$defs/Address"}, null}},
+				"price":   {Ref: "#/$defs/NullableMoney"},
+				"extra":   {Type: TypeSet{TypeObject, TypeNull}, AdditionalProperties: int64Schema},
+				"any":     True,
+			},
+		},
+		{
+			config: GoTypeConfig{ExplicitNull: true},
+			properties: map[string]Schema{
+				"name":    {OneOf: []Schema{{Type: TypeSet{TypeString}}, null}},
+				"tags":    {OneOf: []Schema{{Type: TypeSet{TypeArray}, Items: &Schema{Type: TypeSet{TypeString}}}, null}},
+				"address": {OneOf: []Schema{{Ref: "#/$defs/Address"}, null}},
+				"price":   {Ref: "#/$defs/NullableMoney"},
+				"extra":   {OneOf: []Schema{{Type: TypeSet{TypeObject}, AdditionalProperties: int64Schema}, null}},
+				"any":     True,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		s, err := FromGoTypeWithConfig(test.config, typ)
+		if err != nil {
+			t.Logf("unexpected error: %s", err)
+			t.FailNow()
+		}
+
+		for name, expected := range test.properties {
+			if have := s.Properties[name]; !reflect.DeepEqual(have, expected) {
+				t.Errorf("ExplicitNull=%t: %s:\nhave %s\nneed %s", test.config.ExplicitNull, name, &have, &expected)
+			}
+		}
+	}
+}
+
+func TestFromGoTypeWithConfig_OmitEmptyForbidsNull(t *testing.T) {
+	typ := reflect.TypeOf(struct {
+		Name     *string  `json:"name,omitempty"`
+		Nickname *string  `json:"nickname"`
+		Address  *Address `json:"address,omitempty"`
+		Age      **int8   `json:"age,omitempty"`
+	}{})
+
+	nullableInt8, _ := FromGoType(reflect.TypeOf(ptr(int8(0))))
+
+	tests := []struct {
+		config     GoTypeConfig
+		properties map[string]Schema
+	}{
+		{
+			config: GoTypeConfig{},
+			properties: map[string]Schema{
+				"name":     {Type: TypeSet{TypeString, TypeNull}},
+				"nickname": {Type: TypeSet{TypeString, TypeNull}},
+				"address":  {OneOf: []Schema{{Ref: "#/$defs/Address"}, {Type: TypeSet{TypeNull}}}},
+				"age":      *nullableInt8,
+			},
+		},
+		{
+			config: GoTypeConfig{OmitEmptyForbidsNull: true},
+			properties: map[string]Schema{
+				"name":     {Type: TypeSet{TypeString}},
+				"nickname": {Type: TypeSet{TypeString, TypeNull}},
+				"address":  {Ref: "#/$defs/Address"},
+				"age":      *nullableInt8,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		s, err := FromGoTypeWithConfig(test.config, typ)
+		if err != nil {
+			t.Logf("unexpected error: %s", err)
+			t.FailNow()
+		}
+
+		if !reflect.DeepEqual(s.Properties, test.properties) {
+			t.Errorf("OmitEmptyForbidsNull=%t:\nhave %v\nneed %v", test.config.OmitEmptyForbidsNull, s.Properties, test.properties)
+		}
+		if !reflect.DeepEqual(s.Required, []string{"nickname"}) {
+			t.Errorf("OmitEmptyForbidsNull=%t: unexpected required properties %q", test.config.OmitEmptyForbidsNull, s.Required)
+		}
+	}
+}
+
+func TestFromGoTypeWithConfig_NullabilityPolicy(t *testing.T) {
+	typ := reflect.TypeOf(struct {
+		Pointer          *string  `json:"pointer"`
+		PointerOmitEmpty *string  `json:"pointerOmitEmpty,omitempty"`
+		OmitEmpty        string   `json:"omitEmpty,omitempty"`
+		Neither          string   `json:"neither"`
+		Address          Address  `json:"address,omitempty"`
+		Tags             []string `json:"tags,omitempty"`
+	}{})
+
+	var (
+		str         = Schema{Type: TypeSet{TypeString}}
+		nullableStr = Schema{Type: TypeSet{TypeString, TypeNull}}
+		address     = Schema{Ref: "#/$defs/Address"}
+		tags        = Schema{Type: TypeSet{TypeArray}, Items: &Schema{Type: TypeSet{TypeString}}}
+	)
+
+	tests := map[NullabilityPolicy]map[string]Schema{
+		NullabilityPointerOnly: {
+			"pointer":          nullableStr,
+			"pointerOmitEmpty": nullableStr,
+			"omitEmpty":        str,
+			"neither":          str,
+			"address":          address,
+			"tags":             tags,
+		},
+		NullabilityOmitEmptyImpliesNull: {
+			"pointer":          nullableStr,
+			"pointerOmitEmpty": nullableStr,
+			"omitEmpty":        nullableStr,
+			"neither":          str,
+			"address":          {OneOf: []Schema{address, {Type: TypeSet{TypeNull}}}},
+			"tags":             {Type: TypeSet{TypeArray, TypeNull}, Items: &Schema{Type: TypeSet{TypeString}}},
+		},
+		NullabilityStrict: {
+			"pointer":          nullableStr,
+			"pointerOmitEmpty": str,
+			"omitEmpty":        str,
+			"neither":          str,
+			"address":          address,
+			"tags":             tags,
+		},
+	}
+
+	for policy, expected := range tests {
+		s, err := FromGoTypeWithConfig(GoTypeConfig{NullabilityPolicy: policy}, typ)
+		if err != nil {
+			t.Logf("unexpected error: %s", err)
+			t.FailNow()
+		}
+
+		if !reflect.DeepEqual(s.Properties, expected) {
+			t.Errorf("NullabilityPolicy=%d:\nhave %v\nneed %v", policy, s.Properties, expected)
+		}
+		if !reflect.DeepEqual(s.Required, []string{"pointer", "neither"}) {
+			t.Errorf("NullabilityPolicy=%d: unexpected required properties %q", policy, s.Required)
+		}
+	}
+}
+
+type Resource struct {
+	ID string `json:"id"`
+}
+
+type Named struct {
+	Name string `json:"name,omitempty"`
+}
+
+type Audit struct {
+	Editor string `json:"editor"`
+}
+
+type Article struct {
+	Resource
+	Named
+	*Audit
+	Parent Resource `json:"parent"`
+	Title  string   `json:"title"`
+}
+
+type ShadowedResource struct {
+	Resource
+	ID int `json:"id"`
+}
+
+func TestFromGoTypeWithConfig_EmbeddingAllOf(t *testing.T) {
+	s, err := FromGoTypeWithConfig(GoTypeConfig{EmbeddingStyle: EmbeddingAllOf}, reflect.TypeOf(struct {
+		Article  Article          `json:"article"`
+		Shadowed ShadowedResource `json:"shadowed"`
+	}{}))
+	if err != nil {
+		t.Logf("unexpected error: %s", err)
+		t.FailNow()
+	}
+
+	str := Schema{Type: TypeSet{TypeString}}
+	expected := map[string]Schema{
+		"Resource": {
+			Type:       TypeSet{TypeObject},
+			Properties: map[string]Schema{"id": str},
+			Required:   []string{"id"},
+		},
+		"Named": {
+			Type:       TypeSet{TypeObject},
+			Properties: map[string]Schema{"name": str},
+		},
+		"Article": {AllOf: []Schema{
+			{Ref: "#/$defs/Resource"},
+			{Ref: "#/$defs/Named"},
+			{
+				Type: TypeSet{TypeObject},
+				Properties: map[string]Schema{
+					"editor": str,
+					"parent": {Ref: "#/$defs/Resource"},
+					"title":  str,
+				},
+				Required: []string{"parent", "title"},
+			},
+		}},
+		"ShadowedResource": {
+			Type: TypeSet{TypeObject},
+			Properties: map[string]Schema{"id": {
+				Type:    TypeSet{TypeInteger},
+				Minimum: ptr(json.Number(strconv.FormatInt(math.MinInt64, 10))),
+				Maximum: ptr(json.Number(strconv.FormatInt(math.MaxInt64, 10))),
+			}},
+			Required: []string{"id"},
+		},
+	}
+	if !reflect.DeepEqual(s.Defs, expected) {
+		t.Errorf("\nhave %v\nneed %v", s.Defs, expected)
+	}
+
+	// The default style flattens the embedded structs.
+	s, err = FromGoType(reflect.TypeOf(Article{}))
+	if err != nil {
+		t.Logf("unexpected error: %s", err)
+		t.FailNow()
+	}
+	if names := sortedNames(s.Defs["Article"].Properties); !slices.Equal(names, []string{"editor", "id", "name", "parent", "title"}) {
+		t.Errorf("have properties %v", names)
+	}
+}
+
+func TestFromGoTypeWithConfig_AllowAdditionalProperties(t *testing.T) {
+	typ := reflect.TypeOf(struct {
+		Home   Address           `json:"home"`
+		Points map[int8]bool     `json:"points"`
+		Tags   map[string]string `json:"tags"`
+	}{})
+
+	for _, allow := range []bool{false, true} {
+		s, err := FromGoTypeWithConfig(GoTypeConfig{AllowAdditionalProperties: allow}, typ)
+		if err != nil {
+			t.Logf("unexpected error: %s", err)
+			t.FailNow()
+		}
+
+		var closed *Schema
+		if !allow {
+			closed = &False
+		}
+
+		home := s.Defs["Address"]
+		if !reflect.DeepEqual(s.AdditionalProperties, closed) || !reflect.DeepEqual(home.AdditionalProperties, closed) {
+			t.Errorf("AllowAdditionalProperties=%t: unexpected additionalProperties %s, %s", allow, s.AdditionalProperties, home.AdditionalProperties)
+		}
+
+		// The representation of maps is not affected.
+		if points := s.Properties["points"]; !reflect.DeepEqual(points.AdditionalProperties, &False) {
+			t.Errorf("AllowAdditionalProperties=%t: unexpected schema of points %s", allow, &points)
+		}
+		if tags := s.Properties["tags"]; !reflect.DeepEqual(tags.AdditionalProperties, &Schema{Type: TypeSet{TypeString}}) {
+			t.Errorf("AllowAdditionalProperties=%t: unexpected schema of tags %s", allow, &tags)
+		}
+	}
+}
+
+type pointerMeta struct {
+	Trace string  `json:"trace"`
+	Span  *string `json:"span"`
+}
+
+func TestFromGoTypeWithConfig_PointersOptional(t *testing.T) {
+	typ := reflect.TypeOf(struct {
+		*pointerMeta
+		Name  string   `json:"name"`
+		Email *string  `json:"email"`
+		Phone **string `json:"phone"`
+	}{})
+
+	tests := []struct {
+		config            GoTypeConfig
+		required          []string
+		dependentRequired map[string][]string
+	}{
+		{
+			config:            GoTypeConfig{},
+			required:          []string{"name", "email", "phone"},
+			dependentRequired: map[string][]string{"trace": {"span"}, "span": {"trace"}},
+		},
+		{
+			config:            GoTypeConfig{PointersOptional: true},
+			required:          []string{"name"},
+			dependentRequired: map[string][]string{"span": {"trace"}},
+		},
+	}
+
+	for _, test := range tests {
+		s, err := FromGoTypeWithConfig(test.config, typ)
+		if err != nil {
+			t.Logf("unexpected error: %s", err)
+			t.FailNow()
+		}
+
+		if !reflect.DeepEqual(s.Required, test.required) {
+			t.Errorf("PointersOptional=%t: have required %q, need %q", test.config.PointersOptional, s.Required, test.required)
+		}
+		if !reflect.DeepEqual(s.DependentRequired, test.dependentRequired) {
+			t.Errorf("PointersOptional=%t: have dependentRequired %q, need %q", test.config.PointersOptional, s.DependentRequired, test.dependentRequired)
+		}
+		if email := s.Properties["email"]; !email.IsNullable() {
+			t.Errorf("PointersOptional=%t: expected email to allow null, have %s", test.config.PointersOptional, &email)
+		}
+	}
+}
+
+func TestFromGoTypeWithConfig_RequiredStringsNonEmpty(t *testing.T) {
+	typ := reflect.TypeOf(struct {
+		Name   string  `json:"name"`
+		Nick   string  `json:"nick,omitempty"`
+		Email  *string `json:"email"`
+		Price  Money   `json:"price" jsonschema:"inline"`
+		Labels []string
+	}{})
+
+	for _, enabled := range []bool{false, true} {
+		s, err := FromGoTypeWithConfig(GoTypeConfig{RequiredStringsNonEmpty: enabled}, typ)
+		if err != nil {
+			t.Logf("unexpected error: %s", err)
+			t.FailNow()
+		}
+
+		var minLength *int
+		if enabled {
+			minLength = ptr(1)
+		}
+
+		expected := map[string]Schema{
+			"name":   {Type: TypeSet{TypeString}, MinLength: minLength},
+			"nick":   {Type: TypeSet{TypeString}},
+			"email":  {Type: TypeSet{TypeString, TypeNull}},
+			"price":  {Type: TypeSet{TypeString}, Pattern: ptr(`^-?\d+\.\d{2}$`)},
+			"Labels": {Type: TypeSet{TypeArray}, Items: &Schema{Type: TypeSet{TypeString}}},
+		}
+		if !reflect.DeepEqual(s.Properties, expected) {
+			t.Errorf("RequiredStringsNonEmpty=%t:\nhave %v\nneed %v", enabled, s.Properties, expected)
+		}
+	}
+}
+
+type benchmarkInvoice struct {
+	ID       string              `json:"id"`
+	Customer benchmarkCustomer   `json:"customer"`
+	Lines    []benchmarkLine     `json:"lines"`
+	Notes    map[string]string   `json:"notes,omitempty"`
+	Discount *float64            `json:"discount,omitempty"`
+	Related  []*benchmarkInvoice `json:"related,omitempty"`
+}
+
+type benchmarkCustomer struct {
+	benchmarkAudit
+	Name      string             `json:"name"`
+	Email     *string            `json:"email"`
+	Addresses []benchmarkAddress `json:"addresses"`
+}
+
+type benchmarkAddress struct {
+	Street  string  `json:"street"`
+	City    string  `json:"city"`
+	Zip     string  `json:"zip,omitempty"`
+	Country string  `json:"country"`
+	Lat     float64 `json:"lat"`
+	Lng     float64 `json:"lng"`
+}
+
+type benchmarkLine struct {
+	Product  string          `json:"product"`
+	Quantity uint32          `json:"quantity"`
+	Price    int64           `json:"price"`
+	Tags     []string        `json:"tags"`
+	Attrs    map[int]float32 `json:"attrs"`
+}
+
+type benchmarkAudit struct {
+	CreatedBy string `json:"createdBy"`
+	UpdatedBy string `json:"updatedBy,omitempty"`
+	Revision  int    `json:"revision"`
+}
+
+func BenchmarkFromGoType(b *testing.B) {
+	types := map[string]reflect.Type{
+		"primitive": reflect.TypeOf(int64(0)),
+		"struct":    reflect.TypeOf(benchmarkAddress{}),
+		"graph":     reflect.TypeOf(benchmarkInvoice{}),
+	}
+
+	for name, typ := range types {
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := FromGoType(typ); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestFromGoType_IndependentBounds(t *testing.T) {
+	s, _ := FromGoType(reflect.TypeOf(int8(0)))
+	*s.Minimum = "0"
+	*s.Maximum = "1"
+	s.Type[0] = TypeString
+
+	s, _ = FromGoType(reflect.TypeOf(int8(0)))
+	expected := &Schema{
+		Type:    TypeSet{TypeInteger},
+		Minimum: ptr(json.Number("-128")),
+		Maximum: ptr(json.Number("127")),
+	}
+	if !reflect.DeepEqual(s, expected) {
+		t.Errorf("\nhave %s\nneed %s", s, expected)
+	}
+}
+
+func TestFromGoType_PortableIntBounds(t *testing.T) {
+	tests := map[string]struct {
+		t        reflect.Type
+		min, max json.Number
+	}{
+		"int":  {t: reflect.TypeOf(0), min: "-9223372036854775808", max: "9223372036854775807"},
+		"uint": {t: reflect.TypeOf(uint(0)), min: "0", max: "18446744073709551615"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			s, err := FromGoType(test.t)
+			if err != nil {
+				t.Logf("unexpected error: %s", err)
+				t.FailNow()
+			}
+
+			expected := &Schema{Type: TypeSet{TypeInteger}, Minimum: &test.min, Maximum: &test.max}
+			if !reflect.DeepEqual(s, expected) {
+				t.Errorf("\nhave %s\nneed %s", s, expected)
+			}
+		})
+	}
+}
+
+func TestFromGoTypeWithConfig_OmitIntBounds(t *testing.T) {
+	config := GoTypeConfig{OmitIntBounds: true}
+	tests := map[string]struct {
+		t        reflect.Type
+		expected *Schema
+	}{
+		"int":  {t: reflect.TypeOf(0), expected: &Schema{Type: TypeSet{TypeInteger}}},
+		"uint": {t: reflect.TypeOf(uint(0)), expected: &Schema{Type: TypeSet{TypeInteger}, Minimum: ptr(json.Number("0"))}},
+		"*int": {t: reflect.TypeOf((*int)(nil)), expected: &Schema{Type: TypeSet{TypeInteger, TypeNull}}},
+		"int8": {t: reflect.TypeOf(int8(0)), expected: &Schema{
+			Type:    TypeSet{TypeInteger},
+			Minimum: ptr(json.Number("-128")),
+			Maximum: ptr(json.Number("127")),
+		}},
+		"uint16": {t: reflect.TypeOf(uint16(0)), expected: &Schema{
+			Type:    TypeSet{TypeInteger},
+			Minimum: ptr(json.Number("0")),
+			Maximum: ptr(json.Number("65535")),
+		}},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			s, err := FromGoTypeWithConfig(config, test.t)
+			if err != nil {
+				t.Logf("unexpected error: %s", err)
+				t.FailNow()
+			}
+			if !reflect.DeepEqual(s, test.expected) {
+				t.Errorf("\nhave %s\nneed %s", s, test.expected)
+			}
+		})
+	}
+
+	// The predefined schemas are not modified.
+	if s, _ := FromGoType(reflect.TypeOf(0)); s.Minimum == nil || s.Maximum == nil {
+		t.Errorf("expected bounds without OmitIntBounds, have %s", s)
+	}
+}
+
+func TestFromGoTypeWithConfig_IntegerFormats(t *testing.T) {
+	tests := map[string]struct {
+		in     any
+		format *string
+	}{
+		"int8":   {in: int8(0)},
+		"uint16": {in: uint16(0)},
+		"int32":  {in: int32(0), format: ptr("int32")},
+		"uint32": {in: uint32(0), format: ptr("int32")},
+		"int":    {in: 0, format: ptr("int64")},
+		"uint":   {in: uint(0), format: ptr("int64")},
+		"int64":  {in: int64(0), format: ptr("int64")},
+		"uint64": {in: uint64(0), format: ptr("int64")},
+		"*int32": {in: ptr(int32(0)), format: ptr("int32")},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			plain, err := FromGoType(reflect.TypeOf(test.in))
+			if err != nil {
+				t.Logf("unexpected error: %s", err)
+				t.FailNow()
+			}
+			s, err := FromGoTypeWithConfig(GoTypeConfig{IntegerFormats: true}, reflect.TypeOf(test.in))
+			if err != nil {
+				t.Logf("unexpected error: %s", err)
+				t.FailNow()
+			}
+
+			if plain.Format != nil {
+				t.Errorf("unexpected format without IntegerFormats: %s", plain)
+			}
+			if !reflect.DeepEqual(s.Format, test.format) {
+				t.Errorf("have format %v, need %v", s.Format, test.format)
+			}
+			if s.Minimum == nil || s.Maximum == nil {
+				t.Errorf("expected bounds, have %s", s)
+			}
+		})
+	}
+}
+
+// Level is encoded as its name by MarshalText.
+type Level int
+
+func (l *Level) MarshalText() ([]byte, error) {
+	return []byte([]string{"debug", "info"}[*l]), nil
+}
+
+// Vector implements both encoding.TextMarshaler and json.Marshaler, the latter
+// takes precedence.
+type Vector struct {
+	X, Y float64
+}
+
+func (v Vector) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("%g,%g", v.X, v.Y)), nil
+}
+
+func (v Vector) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]float64{v.X, v.Y})
+}
+
+func TestFromGoTypeWithConfig_DetectTextMarshaler(t *testing.T) {
+	typ := reflect.TypeOf(struct {
+		IP     net.IP `json:"ip"`
+		Level  *Level `json:"level"`
+		Vector Vector `json:"vector"`
+		Name   string `json:"name"`
+	}{})
+
+	s, err := FromGoTypeWithConfig(GoTypeConfig{DetectTextMarshaler: true}, typ)
+	if err != nil {
+		t.Logf("unexpected error: %s", err)
+		t.FailNow()
+	}
+
+	properties := map[string]Schema{
+		"ip":     {Ref: "#/$defs/IP"},
+		"level":  {OneOf: []Schema{{Ref: "#/$defs/Level"}, {Type: TypeSet{TypeNull}}}},
+		"vector": {Ref: "#/$defs/Vector"},
+		"name":   {Type: TypeSet{TypeString}},
+	}
+	if !reflect.DeepEqual(s.Properties, properties) {
+		t.Errorf("\nhave %v\nneed %v", s.Properties, properties)
+	}
+	for _, name := range []string{"IP", "Level"} {
+		if def := s.Defs[name]; !reflect.DeepEqual(def, Schema{Type: TypeSet{TypeString}}) {
+			t.Errorf("%s: unexpected definition %s", name, &def)
+		}
+	}
+	if def := s.Defs["Vector"]; def.Properties == nil {
+		t.Errorf("Vector: expected structural definition, have %s", &def)
+	}
+
+	// By default, the schema is derived from the structure.
+	s, err = FromGoType(typ)
+	if err != nil {
+		t.Logf("unexpected error: %s", err)
+		t.FailNow()
+	}
+	if ip := s.Defs["IP"]; ip.ContentEncoding == nil {
+		t.Errorf("ip: unexpected schema %s", &ip)
+	}
+	if _, ok := s.Defs["Level"]; ok {
+		t.Errorf("unexpected definition of Level")
+	}
+}
+
+// Blob is encoded as a base64 string like any byte slice.
+type Blob []byte
+
+// bit is a byte encoded as a boolean.
+type bit uint8
+
+func (b bit) MarshalJSON() ([]byte, error) {
+	return json.Marshal(b != 0)
+}
+
+func (bit) JSONSchema() *Schema {
+	return &Schema{Type: TypeSet{TypeBoolean}}
+}
+
+func TestFromGoType_ByteSlice(t *testing.T) {
+	s, err := FromGoType(reflect.TypeOf(struct {
+		Data  []byte  `json:"data"`
+		Ptr   *[]byte `json:"ptr"`
+		Blob  Blob    `json:"blob"`
+		Array [4]byte `json:"array"`
+		Bits  []bit   `json:"bits"`
+	}{}))
+	if err != nil {
+		t.Logf("unexpected error: %s", err)
+		t.FailNow()
+	}
+
+	base64 := Schema{Type: TypeSet{TypeString}, ContentEncoding: ptr("base64")}
+	byteSchema := Schema{Type: TypeSet{TypeInteger}, Minimum: ptr(json.Number("0")), Maximum: ptr(json.Number("255"))}
+	expected := &Schema{
+		Type: TypeSet{TypeObject},
+		Properties: map[string]Schema{
+			"data":  base64,
+			"ptr":   {Type: TypeSet{TypeString, TypeNull}, ContentEncoding: ptr("base64")},
+			"blob":  {Ref: "#/$defs/Blob"},
+			"array": {Type: TypeSet{TypeArray}, MaxItems: ptr(4), Items: &byteSchema},
+			"bits":  {Type: TypeSet{TypeArray}, Items: &Schema{Ref: "#/$defs/bit"}},
+		},
+		AdditionalProperties: &False,
+		Required:             []string{"data", "ptr", "blob", "array", "bits"},
+		Defs: map[string]Schema{
+			"Blob": base64,
+			"bit":  {Type: TypeSet{TypeBoolean}},
+		},
+	}
+	if !reflect.DeepEqual(s, expected) {
+		t.Errorf("\nhave %s\nneed %s", s, expected)
+	}
+}
+
+// Point is encoded as a [x, y, label] tuple.
+type Point struct {
+	X, Y  float64
+	Label string
+}
+
+func (p Point) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]any{p.X, p.Y, p.Label})
+}
+
+func (Point) JSONSchema() *Schema {
+	return &Schema{
+		Type: TypeSet{TypeArray},
+		PrefixItems: []Schema{
+			{Type: TypeSet{TypeNumber}},
+			{Type: TypeSet{TypeNumber}},
+			{Type: TypeSet{TypeString}},
+		},
+		Items: &False,
+	}
+}
+
+func TestFromGoType_Tuple(t *testing.T) {
+	s, err := FromGoType(reflect.TypeOf(struct {
+		Path []Point `json:"path"`
+	}{}))
+	if err != nil {
+		t.Logf("unexpected error: %s", err)
+		t.FailNow()
+	}
+
+	if b, _ := json.Marshal(s.Defs["Point"]); string(b) != `{"prefixItems":[{"type":["number"]},{"type":["number"]},{"type":["string"]}],"items":false,"type":["array"]}` {
+		t.Errorf("unexpected tuple schema %s", b)
+	}
+
+	var ptrs []string
+	_ = Walk(s, func(ptr string, _ *Schema) error {
+		ptrs = append(ptrs, ptr)
+		return nil
+	})
+	slices.Sort(ptrs)
+	expected := []string{
+		"/",
+		"/$defs/Point",
+		"/$defs/Point/items",
+		"/$defs/Point/items/not",
+		"/$defs/Point/prefixItems/0",
+		"/$defs/Point/prefixItems/1",
+		"/$defs/Point/prefixItems/2",
+		"/additionalProperties",
+		"/additionalProperties/not",
+		"/properties/path",
+		"/properties/path/items",
+	}
+	if !slices.Equal(ptrs, expected) {
+		t.Errorf("\nhave %q\nneed %q", ptrs, expected)
+	}
+
+	label, err := ResolveReference(ResolveConfig{}, "#/$defs/Point/prefixItems/2", s)
+	if err != nil || !reflect.DeepEqual(label.Type, TypeSet{TypeString}) {
+		t.Errorf("failed to resolve the tuple item: %v, %v", label, err)
+	}
+
+	c := Copy(*s)
+	c.Defs["Point"].PrefixItems[0].Type[0] = TypeInteger
+	if s.Defs["Point"].PrefixItems[0].Type[0] != TypeNumber {
+		t.Errorf("copy shares the tuple items")
+	}
+}
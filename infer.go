@@ -0,0 +1,211 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"slices"
+)
+
+// InferTypes returns the types an instance of s may have. If s declares a type,
+// a copy of it is returned. Otherwise, the types are derived from the values of
+// const and enum. An empty TypeSet is returned if the types cannot be inferred.
+//
+//	{"type":"string"}         // ["string"]
+//	{"const":12}              // ["integer"]
+//	{"enum":["a", 1.5, null]} // ["string", "number", "null"]
+func InferTypes(s *Schema) TypeSet {
+	if len(s.Type) > 0 {
+		return copySlice(s.Type)
+	}
+
+	var values []any
+	if s.Const != nil {
+		values = []any{s.Const}
+	} else {
+		values = s.Enum
+	}
+
+	var ts TypeSet
+	for _, v := range values {
+		if t := InferValueType(v); t != "" && !slices.Contains(ts, t) {
+			ts = append(ts, t)
+		}
+	}
+
+	// Every integer is a number, so integer is redundant if number is present.
+	if slices.Contains(ts, TypeNumber) {
+		ts = slices.DeleteFunc(ts, func(t Type) bool {
+			return t == TypeInteger
+		})
+	}
+	return ts
+}
+
+// InferValueType returns the JSON type of v. Numbers without a fractional part
+// are reported as integer. An empty Type is returned if v cannot be represented
+// in JSON.
+func InferValueType(v any) Type {
+	switch v := v.(type) {
+	case nil:
+		return TypeNull
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			return TypeInteger
+		}
+		if f, err := v.Float64(); err == nil && f == math.Trunc(f) {
+			return TypeInteger
+		}
+		return TypeNumber
+	}
+
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return TypeNull
+		}
+		rv = rv.Elem()
+	}
+
+	switch rv.Kind() {
+	case reflect.Bool:
+		return TypeBoolean
+	case reflect.String:
+		return TypeString
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8,
+		reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return TypeInteger
+	case reflect.Float32, reflect.Float64:
+		if f := rv.Float(); f == math.Trunc(f) && !math.IsInf(f, 0) {
+			return TypeInteger
+		}
+		return TypeNumber
+	case reflect.Slice, reflect.Array:
+		return TypeArray
+	case reflect.Map, reflect.Struct:
+		return TypeObject
+	default:
+		return ""
+	}
+}
+
+// InferConfig configures the schema inference of FromJSONExample.
+type InferConfig struct {
+	// MaxEnumValues infers an enum for locations only holding strings, if there
+	// are at most MaxEnumValues distinct strings. No enum is inferred if zero.
+	MaxEnumValues int
+}
+
+// FromJSONExample infers a schema from one or more example documents. data may
+// contain multiple documents, separated by whitespace. The examples are merged:
+//
+//   - the type set contains the type of every value found at a location
+//   - every property of any object is described by properties
+//   - the properties present in every object are required
+//   - items describes the items of every array
+//
+// An error is returned if data is not valid JSON or contains no document.
+func FromJSONExample(data []byte, config InferConfig) (*Schema, error) {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+
+	root := &example{}
+	for {
+		var v any
+		if err := d.Decode(&v); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("schema.FromJSONExample: %w", err)
+		}
+		root.add(v)
+	}
+
+	if root.values == 0 {
+		return nil, errors.New("schema.FromJSONExample: no example document")
+	}
+	return root.schema(config), nil
+}
+
+// example accumulates the values found at a single location of the examples.
+type example struct {
+	values  int
+	types   map[Type]bool
+	strings []string
+
+	objects    int
+	properties map[string]*example
+	items      *example
+}
+
+func (e *example) add(v any) {
+	t := InferValueType(v)
+	if e.types == nil {
+		e.types = make(map[Type]bool)
+	}
+	e.values++
+	e.types[t] = true
+
+	switch v := v.(type) {
+	case string:
+		if !slices.Contains(e.strings, v) {
+			e.strings = append(e.strings, v)
+		}
+	case map[string]any:
+		if e.properties == nil {
+			e.properties = make(map[string]*example)
+		}
+		e.objects++
+		for k, pv := range v {
+			if e.properties[k] == nil {
+				e.properties[k] = &example{}
+			}
+			e.properties[k].add(pv)
+		}
+	case []any:
+		if e.items == nil && len(v) > 0 {
+			e.items = &example{}
+		}
+		for _, item := range v {
+			e.items.add(item)
+		}
+	}
+}
+
+// exampleTypes is the order of the inferred type sets.
+var exampleTypes = []Type{TypeObject, TypeArray, TypeString, TypeNumber, TypeInteger, TypeBoolean, TypeNull}
+
+func (e *example) schema(config InferConfig) *Schema {
+	s := &Schema{}
+	for _, t := range exampleTypes {
+		// Every integer is a number, so integer is redundant if number is present.
+		if e.types[t] && (t != TypeInteger || !e.types[TypeNumber]) {
+			s.Type = append(s.Type, t)
+		}
+	}
+
+	if e.types[TypeObject] {
+		s.Properties = make(map[string]Schema, len(e.properties))
+		for _, k := range sortedKeys(e.properties) {
+			s.Properties[k] = *e.properties[k].schema(config)
+			if e.properties[k].values == e.objects {
+				s.Required = append(s.Required, k)
+			}
+		}
+	}
+
+	if e.items != nil {
+		s.Items = e.items.schema(config)
+	}
+
+	if len(e.types) == 1 && e.types[TypeString] && len(e.strings) <= config.MaxEnumValues {
+		slices.Sort(e.strings)
+		for _, str := range e.strings {
+			s.Enum = append(s.Enum, str)
+		}
+	}
+	return s
+}
@@ -0,0 +1,144 @@
+package jsonschema_test
+
+import (
+	"encoding/json"
+	. "jsonschema"
+	"reflect"
+	"testing"
+)
+
+func TestInferTypes(t *testing.T) {
+	tests := map[string]struct {
+		schema Schema
+		types  TypeSet
+	}{
+		"declared type":     {schema: Schema{Type: TypeSet{TypeString}, Const: 12}, types: TypeSet{TypeString}},
+		"no type":           {schema: Schema{}, types: nil},
+		"const":             {schema: Schema{Const: float64(12)}, types: TypeSet{TypeInteger}},
+		"const object":      {schema: Schema{Const: map[string]any{}}, types: TypeSet{TypeObject}},
+		"enum":              {schema: Schema{Enum: []any{"a", "b", nil}}, types: TypeSet{TypeString, TypeNull}},
+		"enum number first": {schema: Schema{Enum: []any{1.5, 1}}, types: TypeSet{TypeNumber}},
+		"enum integer last": {schema: Schema{Enum: []any{1, "a", 1.5}}, types: TypeSet{TypeString, TypeNumber}},
+		"enum arrays":       {schema: Schema{Enum: []any{[]any{}, []string{"a"}}}, types: TypeSet{TypeArray}},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if ts := InferTypes(&test.schema); !reflect.DeepEqual(ts, test.types) {
+				t.Errorf("have %v, need %v", ts, test.types)
+			}
+		})
+	}
+}
+
+func TestInferValueType(t *testing.T) {
+	tests := []struct {
+		value any
+		typ   Type
+	}{
+		{value: nil, typ: TypeNull},
+		{value: (*string)(nil), typ: TypeNull},
+		{value: true, typ: TypeBoolean},
+		{value: "", typ: TypeString},
+		{value: ptr("foo"), typ: TypeString},
+		{value: 12, typ: TypeInteger},
+		{value: uint8(12), typ: TypeInteger},
+		{value: float64(12), typ: TypeInteger},
+		{value: 12.5, typ: TypeNumber},
+		{value: json.Number("12"), typ: TypeInteger},
+		{value: json.Number("12.0"), typ: TypeInteger},
+		{value: json.Number("12.5"), typ: TypeNumber},
+		{value: []any{}, typ: TypeArray},
+		{value: [2]int{}, typ: TypeArray},
+		{value: map[string]any{}, typ: TypeObject},
+		{value: struct{}{}, typ: TypeObject},
+		{value: func() {}, typ: ""},
+	}
+
+	for i, test := range tests {
+		if typ := InferValueType(test.value); typ != test.typ {
+			t.Errorf("test #%d: have %q, need %q", i, typ, test.typ)
+		}
+	}
+}
+
+func TestFromJSONExample(t *testing.T) {
+	tests := map[string]struct {
+		data   string
+		config InferConfig
+		schema *Schema
+	}{
+		"scalar":  {data: `12`, schema: &Schema{Type: TypeSet{TypeInteger}}},
+		"numbers": {data: `1 1.5`, schema: &Schema{Type: TypeSet{TypeNumber}}},
+		"object": {
+			data: `{"name": "a", "tags": ["x", "y"], "address": {"city": "b"}, "note": null}`,
+			schema: &Schema{
+				Type: TypeSet{TypeObject},
+				Properties: map[string]Schema{
+					"name": {Type: TypeSet{TypeString}},
+					"tags": {Type: TypeSet{TypeArray}, Items: &Schema{Type: TypeSet{TypeString}}},
+					"address": {
+						Type:       TypeSet{TypeObject},
+						Properties: map[string]Schema{"city": {Type: TypeSet{TypeString}}},
+						Required:   []string{"city"},
+					},
+					"note": {Type: TypeSet{TypeNull}},
+				},
+				Required: []string{"address", "name", "note", "tags"},
+			},
+		},
+		"merged examples": {
+			data: `{"id": 1, "status": "open", "items": []}
+				{"id": 2.5, "status": "closed", "extra": true, "items": [{"a": 1}, {"a": "x", "b": null}]}`,
+			config: InferConfig{MaxEnumValues: 2},
+			schema: &Schema{
+				Type: TypeSet{TypeObject},
+				Properties: map[string]Schema{
+					"id":     {Type: TypeSet{TypeNumber}},
+					"status": {Type: TypeSet{TypeString}, Enum: []any{"closed", "open"}},
+					"extra":  {Type: TypeSet{TypeBoolean}},
+					"items": {Type: TypeSet{TypeArray}, Items: &Schema{
+						Type: TypeSet{TypeObject},
+						Properties: map[string]Schema{
+							"a": {Type: TypeSet{TypeString, TypeInteger}},
+							"b": {Type: TypeSet{TypeNull}},
+						},
+						Required: []string{"a"},
+					}},
+				},
+				Required: []string{"id", "items", "status"},
+			},
+		},
+		"too many enum values": {
+			data:   `"a" "b" "c" "a"`,
+			config: InferConfig{MaxEnumValues: 2},
+			schema: &Schema{Type: TypeSet{TypeString}},
+		},
+		"not only strings": {
+			data:   `"a" null`,
+			config: InferConfig{MaxEnumValues: 2},
+			schema: &Schema{Type: TypeSet{TypeString, TypeNull}},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			s, err := FromJSONExample([]byte(test.data), test.config)
+			if err != nil {
+				t.Logf("unexpected error: %s", err)
+				t.FailNow()
+			}
+			if !reflect.DeepEqual(s, test.schema) {
+				t.Errorf("\nhave %s\nneed %s", s, test.schema)
+			}
+		})
+	}
+}
+
+func TestFromJSONExample_Invalid(t *testing.T) {
+	for _, data := range []string{``, ` `, `{"a": `, `1 }`} {
+		if _, err := FromJSONExample([]byte(data), InferConfig{}); err == nil {
+			t.Errorf("%q: expected error", data)
+		}
+	}
+}
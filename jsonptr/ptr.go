@@ -108,3 +108,27 @@ func IsArrayIndex(segment string) bool {
 	}
 	return true
 }
+
+// Relative returns the pointer to target relative to base, i.e. the suffix of
+// target following base. An error is returned if either pointer is invalid or
+// target does not point to base or one of its descendants.
+//
+//	Relative("/a", "/a/b/c") // "/b/c"
+//	Relative("/a", "/a")     // ""
+func Relative(base, target string) (string, error) {
+	for _, ptr := range []string{base, target} {
+		if err := ValidateJSONPointerFunc(ptr, nil); err != nil {
+			return "", err
+		}
+	}
+
+	if target == base {
+		return "", nil
+	}
+
+	// The separator must follow base, otherwise "/ab" would be a descendant of "/a".
+	if rel, ok := strings.CutPrefix(target, base); ok && rel[0] == '/' {
+		return rel, nil
+	}
+	return "", fmt.Errorf("%q is not a descendant of %q", target, base)
+}
@@ -121,3 +121,37 @@ func ExampleValidateJSONPointerFunc_validatorFunc() {
 	// invalid segment "": segment must be non-empty
 	// true
 }
+
+func TestRelative(t *testing.T) {
+	var tests = []struct {
+		base, target, rel, err string
+	}{
+		{base: "/a", target: "/a/b/c", rel: "/b/c"},
+		{base: "/a", target: "/a", rel: ""},
+		{base: "", target: "/a/b", rel: "/a/b"},
+		{base: "", target: "", rel: ""},
+		{base: "/a", target: "/a/", rel: "/"},
+		{base: "/a~1b", target: "/a~1b/c", rel: "/c"},
+		{base: "/a/", target: "/a//b", rel: "/b"},
+		{base: "/a", target: "/ab", err: `"/ab" is not a descendant of "/a"`},
+		{base: "/a/b", target: "/a", err: `"/a" is not a descendant of "/a/b"`},
+		{base: "/a", target: "#/a/b", err: "invalid JSON pointer: #/a/b"},
+		{base: "/~2", target: "/~2/b", err: `invalid segment "~2": invalid escape sequence: ~2`},
+	}
+
+	for i, test := range tests {
+		rel, err := Relative(test.base, test.target)
+
+		if test.err == "" && err != nil {
+			t.Errorf("test[%d]: expected no error, got %q", i, err)
+		}
+
+		if (test.err != "" && err == nil) || (err != nil && err.Error() != test.err) {
+			t.Errorf("test[%d]: expected error %q, got %q", i, test.err, err)
+		}
+
+		if rel != test.rel {
+			t.Errorf("test[%d]: expected %q, got %q", i, test.rel, rel)
+		}
+	}
+}
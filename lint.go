@@ -0,0 +1,146 @@
+package jsonschema
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// LintIssue is a likely authoring mistake found by Lint.
+type LintIssue struct {
+	Ptr     string // The JSON pointer to the schema, starting from the root schema.
+	Keyword string // The keyword causing the issue.
+	Message string
+}
+
+func (i LintIssue) String() string {
+	return fmt.Sprintf("%s: %s: %s", i.Ptr, i.Keyword, i.Message)
+}
+
+// Lint checks s and all of its subschemas for common authoring mistakes that do not
+// make the schema invalid, but are most likely not intended. The issues are sorted
+// by their pointer. The following is reported:
+//
+//   - const and enum being used together
+//   - duplicate enum values
+//   - invalid patterns, of pattern and the keys of patternProperties
+//   - patterns using constructs of RE2 that differ from ECMA-262, see lintPattern
+//   - unreachable branches, i.e. then if the if schema is false, else if the if
+//     schema is true, and oneOf or anyOf branches that are the false schema
+//   - oneOf or anyOf branches that are the true schema
+//
+// The issues of a branch are reported with the pointer of the branch.
+func Lint(s *Schema) []LintIssue {
+	var issues []LintIssue
+	_ = Walk(s, func(ptr string, schema *Schema) error {
+		report := func(keyword, format string, args ...any) {
+			issues = append(issues, LintIssue{Ptr: ptr, Keyword: keyword, Message: fmt.Sprintf(format, args...)})
+		}
+		reportBranch := func(keyword, branch, format string, args ...any) {
+			issues = append(issues, LintIssue{
+				Ptr:     strings.TrimSuffix(ptr, "/") + "/" + branch,
+				Keyword: keyword,
+				Message: fmt.Sprintf(format, args...),
+			})
+		}
+
+		if schema.If != nil && schema.If.IsFalse() && schema.Then != nil {
+			reportBranch("then", "then", "then is unreachable, if is the false schema")
+		}
+		if schema.If != nil && schema.If.IsTrue() && schema.Else != nil {
+			reportBranch("else", "else", "else is unreachable, if is the true schema")
+		}
+		for _, keyword := range []string{"anyOf", "oneOf"} {
+			branches := schema.AnyOf
+			if keyword == "oneOf" {
+				branches = schema.OneOf
+			}
+			for i := range branches {
+				switch {
+				case branches[i].IsFalse():
+					reportBranch(keyword, fmt.Sprintf("%s/%d", keyword, i), "branch %d is the false schema and never matches", i)
+				case branches[i].IsTrue() && keyword == "anyOf":
+					reportBranch(keyword, fmt.Sprintf("%s/%d", keyword, i), "branch %d is the true schema, anyOf is always satisfied", i)
+				case branches[i].IsTrue():
+					reportBranch(keyword, fmt.Sprintf("%s/%d", keyword, i), "branch %d is the true schema, no other branch may match", i)
+				}
+			}
+		}
+
+		if schema.Const != nil && schema.Enum != nil {
+			report("const", "const and enum are used together")
+		}
+
+		seen := make(map[string]int, len(schema.Enum))
+		for i, v := range schema.Enum {
+			key, err := canonicalJSON(v)
+			if err != nil {
+				report("enum", "value %d cannot be encoded: %s", i, err)
+				continue
+			}
+			if j, ok := seen[key]; ok {
+				report("enum", "value %d is a duplicate of value %d", i, j)
+				continue
+			}
+			seen[key] = i
+		}
+
+		if schema.Pattern != nil {
+			for _, msg := range lintPattern(*schema.Pattern) {
+				report("pattern", "%s", msg)
+			}
+		}
+		for _, p := range sortedKeys(schema.PatternProperties) {
+			for _, msg := range lintPattern(p) {
+				report("patternProperties", "%s", msg)
+			}
+		}
+		return nil
+	})
+
+	slices.SortStableFunc(issues, func(a, b LintIssue) int {
+		return cmp.Compare(a.Ptr, b.Ptr)
+	})
+	return issues
+}
+
+// lintPattern returns the issues of the regular expression p. Schemas use the
+// ECMA-262 dialect, while patterns are compiled using RE2. The constructs that
+// are commonly used, but have a different meaning in both dialects are reported:
+//
+//   - \A, \z and \Q...\E are literals in ECMA-262
+//   - \s and \S only match ASCII whitespace in RE2
+//   - inline flags like (?i) are not supported by ECMA-262
+//   - POSIX classes like [[:alpha:]] are not supported by ECMA-262
+func lintPattern(p string) []string {
+	if _, err := regexp.Compile(p); err != nil {
+		return []string{fmt.Sprintf("invalid pattern %q: %s", p, err)}
+	}
+
+	var msgs []string
+	add := func(format string, args ...any) {
+		if msg := fmt.Sprintf(format, args...); !slices.Contains(msgs, msg) {
+			msgs = append(msgs, msg)
+		}
+	}
+
+	for i := 0; i < len(p); i++ {
+		switch {
+		case p[i] == '\\' && i+1 < len(p):
+			i++
+			switch p[i] {
+			case 'A', 'z', 'Q':
+				add("pattern %q: \\%c is a literal %c in ECMA-262", p, p[i], p[i])
+			case 's', 'S':
+				add("pattern %q: \\%c only matches ASCII whitespace in RE2", p, p[i])
+			}
+		case strings.HasPrefix(p[i:], "(?") && i+2 < len(p) && strings.IndexByte("imsU-", p[i+2]) >= 0:
+			add("pattern %q: inline flags are not supported by ECMA-262", p)
+		case strings.HasPrefix(p[i:], "[[:"):
+			add("pattern %q: POSIX classes are not supported by ECMA-262", p)
+		}
+	}
+	return msgs
+}
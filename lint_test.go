@@ -0,0 +1,96 @@
+package jsonschema_test
+
+import (
+	"encoding/json"
+	. "jsonschema"
+	"reflect"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	tests := map[string]struct {
+		schema Schema
+		issues []LintIssue
+	}{
+		"no issues": {
+			schema: Schema{Enum: []any{"a", "b", 1, json.Number("1.5")}},
+		},
+		"const and enum": {
+			schema: Schema{Const: "a", Enum: []any{"a"}},
+			issues: []LintIssue{{Ptr: "/", Keyword: "const", Message: "const and enum are used together"}},
+		},
+		"duplicate enum values": {
+			schema: Schema{Enum: []any{"a", 1, json.Number("1.0"), "a", map[string]any{"b": 1, "a": 2}, map[string]any{"a": 2, "b": 1.0}}},
+			issues: []LintIssue{
+				{Ptr: "/", Keyword: "enum", Message: "value 2 is a duplicate of value 1"},
+				{Ptr: "/", Keyword: "enum", Message: "value 3 is a duplicate of value 0"},
+				{Ptr: "/", Keyword: "enum", Message: "value 5 is a duplicate of value 4"},
+			},
+		},
+		"subschemas": {
+			schema: Schema{
+				Properties: map[string]Schema{
+					"a": {Enum: []any{true, true}},
+				},
+				Defs: map[string]Schema{
+					"b": {Items: &Schema{Const: 1, Enum: []any{1}}},
+				},
+			},
+			issues: []LintIssue{
+				{Ptr: "/$defs/b/items", Keyword: "const", Message: "const and enum are used together"},
+				{Ptr: "/properties/a", Keyword: "enum", Message: "value 1 is a duplicate of value 0"},
+			},
+		},
+		"invalid patterns": {
+			schema: Schema{
+				Pattern:           ptr("(a"),
+				PatternProperties: map[string]Schema{"^x-(?=y)": True, "^[a-z]+$": True},
+			},
+			issues: []LintIssue{
+				{Ptr: "/", Keyword: "pattern", Message: "invalid pattern \"(a\": error parsing regexp: missing closing ): `(a`"},
+				{Ptr: "/", Keyword: "patternProperties", Message: "invalid pattern \"^x-(?=y)\": error parsing regexp: invalid or unsupported Perl syntax: `(?=`"},
+			},
+		},
+		"dialect differences": {
+			schema: Schema{Properties: map[string]Schema{
+				"a": {Pattern: ptr(`\Aa\s+b\z`)},
+				"b": {Pattern: ptr(`(?i)^[[:alpha:]]+$`)},
+				"c": {Pattern: ptr(`^\\s(?:a|b)\d$`)},
+			}},
+			issues: []LintIssue{
+				{Ptr: "/properties/a", Keyword: "pattern", Message: `pattern "\\Aa\\s+b\\z": \A is a literal A in ECMA-262`},
+				{Ptr: "/properties/a", Keyword: "pattern", Message: `pattern "\\Aa\\s+b\\z": \s only matches ASCII whitespace in RE2`},
+				{Ptr: "/properties/a", Keyword: "pattern", Message: `pattern "\\Aa\\s+b\\z": \z is a literal z in ECMA-262`},
+				{Ptr: "/properties/b", Keyword: "pattern", Message: `pattern "(?i)^[[:alpha:]]+$": inline flags are not supported by ECMA-262`},
+				{Ptr: "/properties/b", Keyword: "pattern", Message: `pattern "(?i)^[[:alpha:]]+$": POSIX classes are not supported by ECMA-262`},
+			},
+		},
+		"unreachable branches": {
+			schema: Schema{
+				If:   &False,
+				Then: &Schema{Type: TypeSet{TypeString}},
+				Properties: map[string]Schema{
+					"a": {If: &True, Then: &True, Else: &Schema{MinLength: ptr(1)}},
+					"b": {If: &Schema{Type: TypeSet{TypeString}}, Then: &True, Else: &False},
+				},
+				OneOf: []Schema{{Type: TypeSet{TypeString}}, False, True},
+				AnyOf: []Schema{True, {Type: TypeSet{TypeNull}}},
+			},
+			issues: []LintIssue{
+				{Ptr: "/anyOf/0", Keyword: "anyOf", Message: "branch 0 is the true schema, anyOf is always satisfied"},
+				{Ptr: "/oneOf/1", Keyword: "oneOf", Message: "branch 1 is the false schema and never matches"},
+				{Ptr: "/oneOf/2", Keyword: "oneOf", Message: "branch 2 is the true schema, no other branch may match"},
+				{Ptr: "/properties/a/else", Keyword: "else", Message: "else is unreachable, if is the true schema"},
+				{Ptr: "/then", Keyword: "then", Message: "then is unreachable, if is the false schema"},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if issues := Lint(&test.schema); !reflect.DeepEqual(issues, test.issues) {
+				t.Errorf("\nhave %v\nneed %v", issues, test.issues)
+			}
+		})
+	}
+}
@@ -6,7 +6,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io/fs"
 	"net/url"
+	"path"
 	"strings"
 )
 
@@ -24,32 +26,258 @@ func (f LoaderFunc) Load(ctx context.Context, uri *url.URL) (*Schema, error) {
 	return f(ctx, uri)
 }
 
+// RawLoader is an optional interface that can be implemented by a Loader to
+// provide the raw schema document alongside the parsed Schema.
+type RawLoader interface {
+	LoadRaw(ctx context.Context, uri *url.URL) ([]byte, *Schema, error)
+}
+
+type embeddedLoader struct {
+	fs embed.FS
+}
+
+func (l embeddedLoader) Load(ctx context.Context, uri *url.URL) (*Schema, error) {
+	_, s, err := l.LoadRaw(ctx, uri)
+	return s, err
+}
+
+func (l embeddedLoader) LoadRaw(_ context.Context, uri *url.URL) ([]byte, *Schema, error) {
+	if uri.Scheme != "file" {
+		return nil, nil, UnsupportedURI
+	}
+
+	d, err := l.fs.ReadFile(strings.TrimPrefix(uri.Path, "/"))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	*uri = url.URL{Fragment: uri.Fragment}
+
+	s := &Schema{}
+	if err = json.Unmarshal(d, s); err != nil {
+		return nil, nil, fmt.Errorf("failed to read schema: %w", err)
+	}
+
+	return d, s, nil
+}
+
 // NewEmbeddedLoader returns a Loader that searches fs for the URI. This loader will
-// return UnsupportedURI if the Scheme is not "file".
+// return UnsupportedURI if the Scheme is not "file". The returned Loader implements
+// RawLoader.
 //
 // Does not support distinct schema resources within a single schema document.
 func NewEmbeddedLoader(fs embed.FS) Loader {
+	return embeddedLoader{fs: fs}
+}
+
+// NewYAMLLoader returns a Loader that reads schema documents written in YAML from
+// fsys. Like NewEmbeddedLoader, the path of the URI is used as path within fsys and
+// UnsupportedURI is returned if the scheme is not "file" or the file extension is
+// neither .yaml nor .yml.
+//
+// The package does not include a YAML decoder, unmarshal decodes a document into
+// a generic value, e.g. the Unmarshal function of a YAML package. The decoder is
+// expected to expand anchors and aliases. Maps with non-string keys, as decoded
+// by some packages, are converted to objects with string keys.
+func NewYAMLLoader(fsys fs.FS, unmarshal func(data []byte, v any) error) Loader {
 	return LoaderFunc(func(_ context.Context, uri *url.URL) (*Schema, error) {
-		if uri.Scheme != "file" {
+		if ext := path.Ext(uri.Path); uri.Scheme != "file" || (ext != ".yaml" && ext != ".yml") {
 			return nil, UnsupportedURI
 		}
 
-		d, err := fs.ReadFile(strings.TrimPrefix(uri.Path, "/"))
+		d, err := fs.ReadFile(fsys, strings.TrimPrefix(uri.Path, "/"))
 		if err != nil {
 			return nil, err
 		}
 
-		*uri = url.URL{Fragment: uri.Fragment}
+		var v any
+		if err = unmarshal(d, &v); err != nil {
+			return nil, fmt.Errorf("failed to read schema: %w", err)
+		}
+		if v, err = yamlToJSON(v); err != nil {
+			return nil, fmt.Errorf("failed to read schema: %w", err)
+		}
+
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read schema: %w", err)
+		}
 
 		s := &Schema{}
-		if err = json.Unmarshal(d, s); err != nil {
+		if err = json.Unmarshal(b, s); err != nil {
 			return nil, fmt.Errorf("failed to read schema: %w", err)
 		}
 
+		*uri = url.URL{Fragment: uri.Fragment}
+		return s, nil
+	})
+}
+
+// yamlToJSON converts the maps of the decoded YAML value v to objects with string
+// keys. Scalar keys are formatted, an error is returned for other keys.
+func yamlToJSON(v any) (any, error) {
+	switch v := v.(type) {
+	case map[any]any:
+		m := make(map[string]any, len(v))
+		for k, e := range v {
+			switch k.(type) {
+			case string, bool, int, int64, uint64, float64:
+			default:
+				return nil, fmt.Errorf("unsupported key %v of type %T", k, k)
+			}
+
+			var err error
+			if m[fmt.Sprint(k)], err = yamlToJSON(e); err != nil {
+				return nil, err
+			}
+		}
+		return m, nil
+	case map[string]any:
+		for k, e := range v {
+			var err error
+			if v[k], err = yamlToJSON(e); err != nil {
+				return nil, err
+			}
+		}
+	case []any:
+		for i := range v {
+			var err error
+			if v[i], err = yamlToJSON(v[i]); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return v, nil
+}
+
+// NewIndexedLoader returns a Loader that serves the schema documents of fsys by
+// their $id instead of their location. Every file with the extension .json is
+// read and indexed once by its $id and the $id of every embedded schema resource.
+// Documents without an $id are ignored. An error is returned if a document cannot
+// be parsed, its $id is not absolute or the $id of the document or of an embedded
+// resource is used more than once.
+//
+// The returned Loader returns UnsupportedURI for unknown URIs.
+func NewIndexedLoader(fsys fs.FS) (Loader, error) {
+	set := &SchemaSet{}
+	files := make(map[string]string) // by base URI
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || path.Ext(name) != ".json" {
+			return err
+		}
+
+		b, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+
+		s := &Schema{}
+		if err = json.Unmarshal(b, s); err != nil {
+			return fmt.Errorf("failed to read schema %s: %w", name, err)
+		}
+		if s.ID == "" {
+			return nil
+		}
+
+		if err = set.Add(s.ID, s); err != nil {
+			return fmt.Errorf("schema %s: %w", name, err)
+		}
+
+		// The document and its embedded resources are indexed by base URI, none
+		// of them may replace a resource indexed before.
+		uris := []string{s.ID}
+		ids, _ := ComputeIdentifiers(*s)
+		for _, ptr := range sortedKeys(ids) {
+			if id := ids[ptr]; id.BaseURI+"#" == id.CanonResourcePointerURI {
+				uris = append(uris, id.BaseURI)
+			}
+		}
+		for _, uri := range uris {
+			if other, ok := files[uri]; ok {
+				return fmt.Errorf("schema %s: duplicate $id %q, already used by %s", name, uri, other)
+			}
+			files[uri] = name
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("schema.NewIndexedLoader: %w", err)
+	}
+	return set, nil
+}
+
+// NewTemplateLoader returns a Loader that rewrites a URI into another URI using
+// template and calls next with the rewritten URI. The template contains variables
+// in braces, which are replaced by the path escaped values returned by vars:
+//
+//	NewTemplateLoader("https://registry.internal/schemas/{name}/{version}.json", vars, next)
+//
+// If vars returns nil, the URI is passed to next unchanged. An error is returned if
+// vars does not return a value for every variable of the template. The fragment of
+// the URI is kept.
+func NewTemplateLoader(template string, vars func(uri *url.URL) map[string]string, next Loader) Loader {
+	return LoaderFunc(func(ctx context.Context, uri *url.URL) (*Schema, error) {
+		if next == nil {
+			return nil, UnsupportedURI
+		}
+
+		values := vars(uri)
+		if values == nil {
+			return next.Load(ctx, uri)
+		}
+
+		raw, err := expandTemplate(template, values)
+		if err != nil {
+			return nil, fmt.Errorf("failed to rewrite %q: %w", uri, err)
+		}
+
+		u, err := url.Parse(raw)
+		if err != nil {
+			return nil, fmt.Errorf("failed to rewrite %q: %w", uri, err)
+		}
+		u.Fragment = uri.Fragment
+
+		s, err := next.Load(ctx, u)
+		if err != nil {
+			return nil, err
+		}
+
+		// The rewritten URI is only passed on if next replaced it with a reference
+		// relative to the loaded schema, the schema is identified by the original URI.
+		if !u.IsAbs() {
+			*uri = *u
+		}
 		return s, nil
 	})
 }
 
+// expandTemplate replaces the variables of template by their path escaped values.
+func expandTemplate(template string, values map[string]string) (string, error) {
+	var sb strings.Builder
+	for {
+		start := strings.IndexByte(template, '{')
+		if start < 0 {
+			sb.WriteString(template)
+			return sb.String(), nil
+		}
+
+		end := strings.IndexByte(template[start:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("unterminated template variable at %d", start)
+		}
+
+		name := template[start+1 : start+end]
+		value, ok := values[name]
+		if !ok {
+			return "", fmt.Errorf("missing template variable %q", name)
+		}
+
+		sb.WriteString(template[:start])
+		sb.WriteString(url.PathEscape(value))
+		template = template[start+end+1:]
+	}
+}
+
 // NewLocalLoader returns a loader that checks the URI against identifiable sub-schemas that
 // are located within the root schema. If a sub-schema is found, the URI is replaced with
 // a new URI relative to the resolved schema. If no schema is found, the next Loader is called.
@@ -65,7 +293,7 @@ func NewLocalLoader(root *Schema, next Loader) Loader {
 	prefetched := make(map[string]*Schema)
 	for s, identifiers := range ids {
 		if identifiers.BaseURI+"#" == identifiers.CanonResourcePointerURI {
-			prefetched[identifiers.BaseURI], _ = resolveRef(ResolveConfig{ignoreRefs: true}, root,
+			prefetched[identifiers.BaseURI], _, _ = resolveRef(ResolveConfig{ignoreRefs: true}, root,
 				getUnescapedPath(s), 0)
 		}
 	}
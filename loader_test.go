@@ -1,12 +1,18 @@
 package jsonschema_test
 
 import (
+	"bytes"
+	"context"
 	"embed"
+	"encoding/json"
 	"errors"
 	. "jsonschema"
 	"net/url"
 	"reflect"
+	"strconv"
+	"strings"
 	"testing"
+	"testing/fstest"
 )
 
 //go:embed testdata/*
@@ -31,7 +37,7 @@ func TestNewEmbeddedLoader(t *testing.T) {
 
 	expected := &Schema{
 		ID:          "file:///testdata/miscellaneous-examples/arrays.schema.json",
-		Schema:      "https://json-schema.org/draft/2020-12/schema",
+		Schema:      string(Draft2020Schema),
 		Comment:     "https://json-schema.org/learn/miscellaneous-examples#arrays-of-things",
 		Description: "A representation of a person, company, organization, or place",
 		Type:        TypeSet{TypeObject},
@@ -83,6 +89,300 @@ func TestNewEmbeddedLoader(t *testing.T) {
 	}
 }
 
+func TestNewEmbeddedLoader_LoadRaw(t *testing.T) {
+	loader, ok := NewEmbeddedLoader(testdataFS).(RawLoader)
+	if !ok {
+		t.Logf("expected loader to implement RawLoader")
+		t.FailNow()
+	}
+
+	uri, _ := url.Parse("https://example.com/arrays.schema.json")
+	if _, _, err := loader.LoadRaw(nil, uri); !errors.Is(err, UnsupportedURI) {
+		t.Logf("expected UnsupportedURI")
+		t.FailNow()
+	}
+
+	const name = "testdata/miscellaneous-examples/arrays.schema.json"
+	uri, _ = url.Parse("file:///" + name)
+	raw, schema, err := loader.LoadRaw(nil, uri)
+	if err != nil {
+		t.Logf("expected schema, got %s", err)
+		t.FailNow()
+	}
+
+	expected, _ := testdataFS.ReadFile(name)
+	if !bytes.Equal(raw, expected) {
+		t.Logf("have: %s", raw)
+		t.Logf("need: %s", expected)
+		t.FailNow()
+	}
+
+	if schema == nil || schema.ID != "file:///"+name {
+		t.Logf("expected parsed schema, got %s", schema)
+		t.FailNow()
+	}
+}
+
+func TestNewIndexedLoader(t *testing.T) {
+	fsys := fstest.MapFS{
+		"person.json": {Data: []byte(`{
+			"$id": "https://example.com/schemas/person",
+			"properties": {"address": {"$ref": "address"}},
+			"$defs": {"address": {"$id": "address", "type": "object"}}
+		}`)},
+		"v2/nested/order.json": {Data: []byte(`{"$id": "https://example.com/orders/v2", "type": "object"}`)},
+		"anonymous.json":       {Data: []byte(`{"type": "string"}`)},
+		"README.md":            {Data: []byte(`not a schema`)},
+	}
+
+	loader, err := NewIndexedLoader(fsys)
+	if err != nil {
+		t.Logf("unexpected error: %s", err)
+		t.FailNow()
+	}
+
+	tests := map[string]struct {
+		uri, rest, id string
+	}{
+		"document":          {uri: "https://example.com/schemas/person", id: "https://example.com/schemas/person"},
+		"nested document":   {uri: "https://example.com/orders/v2", id: "https://example.com/orders/v2"},
+		"embedded resource": {uri: "https://example.com/schemas/address", id: "https://example.com/schemas/address"},
+		"with fragment":     {uri: "https://example.com/schemas/person#/properties/address", rest: "#/properties/address", id: "https://example.com/schemas/person"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			uri, _ := url.Parse(test.uri)
+			s, err := loader.Load(context.Background(), uri)
+			if err != nil {
+				t.Logf("unexpected error: %s", err)
+				t.FailNow()
+			}
+
+			if s.ID != test.id {
+				t.Errorf("have $id %q, need %q", s.ID, test.id)
+			}
+			if uri.String() != test.rest {
+				t.Errorf("have uri %q, need %q", uri, test.rest)
+			}
+		})
+	}
+
+	uri, _ := url.Parse("file:///person.json")
+	if _, err = loader.Load(context.Background(), uri); !errors.Is(err, UnsupportedURI) {
+		t.Errorf("have %v, need UnsupportedURI", err)
+	}
+}
+
+func TestNewIndexedLoader_Invalid(t *testing.T) {
+	tests := map[string]fstest.MapFS{
+		"invalid json": {
+			"a.json": {Data: []byte(`{"$id": `)},
+		},
+		"relative id": {
+			"a.json": {Data: []byte(`{"$id": "a.json"}`)},
+		},
+	}
+
+	for name, fsys := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewIndexedLoader(fsys); err == nil {
+				t.Errorf("expected error")
+			}
+		})
+	}
+}
+
+func TestNewIndexedLoader_Duplicate(t *testing.T) {
+	tests := map[string]struct {
+		fsys fstest.MapFS
+		err  string
+	}{
+		"document": {
+			fsys: fstest.MapFS{
+				"a.json":     {Data: []byte(`{"$id": "https://example.com/a"}`)},
+				"sub/b.json": {Data: []byte(`{"$id": "https://example.com/a"}`)},
+			},
+			err: `schema.NewIndexedLoader: schema sub/b.json: duplicate $id "https://example.com/a", already used by a.json`,
+		},
+		"embedded resource": {
+			fsys: fstest.MapFS{
+				"a.json": {Data: []byte(`{"$id": "https://example.com/a", "$defs": {"b": {"$id": "b"}}}`)},
+				"b.json": {Data: []byte(`{"$id": "https://example.com/b"}`)},
+			},
+			err: `schema.NewIndexedLoader: schema b.json: duplicate $id "https://example.com/b", already used by a.json`,
+		},
+		"embedded resources": {
+			fsys: fstest.MapFS{
+				"a.json": {Data: []byte(`{"$id": "https://example.com/a", "$defs": {"c": {"$id": "c"}}}`)},
+				"b.json": {Data: []byte(`{"$id": "https://example.com/b", "$defs": {"c": {"$id": "https://example.com/c"}}}`)},
+			},
+			err: `schema.NewIndexedLoader: schema b.json: duplicate $id "https://example.com/c", already used by a.json`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewIndexedLoader(test.fsys)
+			if err == nil || err.Error() != test.err {
+				t.Errorf("\nhave %v\nneed %s", err, test.err)
+			}
+		})
+	}
+}
+
+// yamlV2Unmarshal imitates YAML packages decoding mappings into maps with keys
+// of any type, it decodes JSON as a subset of YAML.
+func yamlV2Unmarshal(data []byte, v any) error {
+	var convert func(v any) any
+	convert = func(v any) any {
+		switch v := v.(type) {
+		case map[string]any:
+			m := make(map[any]any, len(v))
+			for k, e := range v {
+				if n, err := strconv.Atoi(k); err == nil {
+					m[n] = convert(e)
+				} else {
+					m[k] = convert(e)
+				}
+			}
+			return m
+		case []any:
+			for i := range v {
+				v[i] = convert(v[i])
+			}
+		}
+		return v
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return err
+	}
+	*v.(*any) = convert(*v.(*any))
+	return nil
+}
+
+func TestNewYAMLLoader(t *testing.T) {
+	fsys := fstest.MapFS{
+		"schemas/person.yaml": {Data: []byte(`{"type": "object", "properties": {"name": {"type": "string"}}, "patternProperties": {"200": true}}`)},
+		"schemas/person.json": {Data: []byte(`{}`)},
+		"schemas/invalid.yml": {Data: []byte(`{"type": 12}`)},
+	}
+	loader := NewYAMLLoader(fsys, yamlV2Unmarshal)
+
+	uri, _ := url.Parse("file:///schemas/person.yaml#/properties/name")
+	s, err := loader.Load(context.Background(), uri)
+	if err != nil {
+		t.Logf("unexpected error: %s", err)
+		t.FailNow()
+	}
+
+	expected := &Schema{
+		Type:              TypeSet{TypeObject},
+		Properties:        map[string]Schema{"name": {Type: TypeSet{TypeString}}},
+		PatternProperties: map[string]Schema{"200": True},
+	}
+	if !reflect.DeepEqual(s, expected) {
+		t.Errorf("\nhave %s\nneed %s", s, expected)
+	}
+	if uri.String() != "#/properties/name" {
+		t.Errorf("have uri %q, need %q", uri, "#/properties/name")
+	}
+
+	for _, u := range []string{"https://example.com/schemas/person.yaml", "file:///schemas/person.json"} {
+		uri, _ = url.Parse(u)
+		if _, err = loader.Load(context.Background(), uri); !errors.Is(err, UnsupportedURI) {
+			t.Errorf("%s: have %v, need UnsupportedURI", u, err)
+		}
+	}
+
+	for _, u := range []string{"file:///schemas/invalid.yml", "file:///schemas/missing.yml"} {
+		uri, _ = url.Parse(u)
+		if _, err = loader.Load(context.Background(), uri); err == nil || errors.Is(err, UnsupportedURI) {
+			t.Errorf("%s: expected error, have %v", u, err)
+		}
+	}
+}
+
+func TestNewTemplateLoader(t *testing.T) {
+	var loaded []string
+	next := LoaderFunc(func(_ context.Context, uri *url.URL) (*Schema, error) {
+		loaded = append(loaded, uri.String())
+		return &Schema{ID: uri.String()}, nil
+	})
+
+	vars := func(uri *url.URL) map[string]string {
+		name, version, ok := strings.Cut(uri.Opaque, "@")
+		if uri.Scheme != "schema" {
+			return nil
+		} else if !ok {
+			return map[string]string{"name": name}
+		}
+		return map[string]string{"name": name, "version": version}
+	}
+	loader := NewTemplateLoader("https://registry.internal/schemas/{name}/{version}.json", vars, next)
+
+	tests := []struct {
+		uri, loaded, rewritten, err string
+	}{
+		{
+			uri:       "schema:person@v2",
+			loaded:    "https://registry.internal/schemas/person/v2.json",
+			rewritten: "schema:person@v2",
+		},
+		{
+			uri:       "schema:person@v2#/$defs/name",
+			loaded:    "https://registry.internal/schemas/person/v2.json#/$defs/name",
+			rewritten: "schema:person@v2#/$defs/name",
+		},
+		{
+			uri:       "schema:a b@1.0",
+			loaded:    "https://registry.internal/schemas/a%20b/1.0.json",
+			rewritten: "schema:a b@1.0",
+		},
+		{
+			uri:       "https://example.com/person.json",
+			loaded:    "https://example.com/person.json",
+			rewritten: "https://example.com/person.json",
+		},
+		{
+			uri: "schema:person",
+			err: `failed to rewrite "schema:person": missing template variable "version"`,
+		},
+	}
+
+	for _, test := range tests {
+		loaded = nil
+		uri := &url.URL{Scheme: "schema"}
+		if strings.HasPrefix(test.uri, "schema:") {
+			uri.Opaque, uri.Fragment, _ = strings.Cut(strings.TrimPrefix(test.uri, "schema:"), "#")
+		} else {
+			uri, _ = url.Parse(test.uri)
+		}
+
+		_, err := loader.Load(context.Background(), uri)
+		if test.err != "" {
+			if err == nil || err.Error() != test.err {
+				t.Errorf("%s: expected error %q, got %v", test.uri, test.err, err)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", test.uri, err)
+		} else if !reflect.DeepEqual(loaded, []string{test.loaded}) {
+			t.Errorf("%s: have loaded %q, need %q", test.uri, loaded, test.loaded)
+		} else if uri.String() != test.rewritten {
+			t.Errorf("%s: have uri %q, need %q", test.uri, uri, test.rewritten)
+		}
+	}
+
+	if _, err := NewTemplateLoader("https://registry.internal/{name", vars, next).
+		Load(context.Background(), &url.URL{Scheme: "schema", Opaque: "person@v1"}); err == nil {
+		t.Errorf("expected an error for an invalid template")
+	}
+}
+
 func ptr[T any](v T) *T {
 	return &v
 }
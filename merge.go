@@ -0,0 +1,172 @@
+package jsonschema
+
+import (
+	"reflect"
+	"slices"
+)
+
+// Merge returns the schema of override layered onto base, e.g. to adjust the
+// schema of a type for a single use. Neither schema is modified, the result is
+// independent of both:
+//
+//   - the annotations title, description, $comment, default, deprecated,
+//     readOnly, writeOnly and examples of override replace those of base
+//   - type is the intersection of both type sets, integer being a number
+//   - required and allOf contain the entries of both schemas, dependentRequired
+//     the dependencies of both schemas
+//   - properties and $defs are merged by name, a property or definition present
+//     in both schemas is merged recursively
+//   - any other keyword of either schema is kept if the other schema does not
+//     declare it or declares it equally, see Equal
+//
+// A keyword declared differently by both schemas is kept from base, the keyword
+// of override is applied by an additional entry of allOf instead, so that both
+// apply. If either schema is False, or both type sets are disjoint, False is
+// returned.
+//
+//	{"type":["string","null"],"minLength":1} and {"type":"string","minLength":2}
+//	// {"type":"string","minLength":1,"allOf":[{"minLength":2}]}
+func Merge(base, override Schema) Schema {
+	if base.IsFalse() || override.IsFalse() {
+		return Copy(False)
+	}
+
+	s, o := Copy(base), Copy(override)
+
+	if o.Title != "" {
+		s.Title = o.Title
+	}
+	if o.Description != "" {
+		s.Description = o.Description
+	}
+	if o.Comment != "" {
+		s.Comment = o.Comment
+	}
+	if o.Default != nil {
+		s.Default = o.Default
+	}
+	if o.Deprecated != nil {
+		s.Deprecated = o.Deprecated
+	}
+	if o.ReadOnly != nil {
+		s.ReadOnly = o.ReadOnly
+	}
+	if o.WriteOnly != nil {
+		s.WriteOnly = o.WriteOnly
+	}
+	if o.Examples != nil {
+		s.Examples = o.Examples
+	}
+
+	if len(s.Type) == 0 {
+		s.Type = o.Type
+	} else if len(o.Type) != 0 {
+		if s.Type = intersectTypes(s.Type, o.Type); len(s.Type) == 0 {
+			return Copy(False)
+		}
+	}
+
+	for _, name := range o.Required {
+		if !slices.Contains(s.Required, name) {
+			s.Required = append(s.Required, name)
+		}
+	}
+	for name, deps := range o.DependentRequired {
+		if s.DependentRequired == nil {
+			s.DependentRequired = make(map[string][]string, len(o.DependentRequired))
+		}
+		for _, dep := range deps {
+			if !slices.Contains(s.DependentRequired[name], dep) {
+				s.DependentRequired[name] = append(s.DependentRequired[name], dep)
+			}
+		}
+	}
+
+	s.Properties = mergeSchemas(s.Properties, o.Properties)
+	s.Defs = mergeSchemas(s.Defs, o.Defs)
+	s.AllOf = append(s.AllOf, o.AllOf...)
+
+	// The remaining keywords are merged alike, those declared differently are
+	// collected into conflicts.
+	var (
+		conflicts Schema
+		sv        = reflect.ValueOf(&s).Elem()
+		ov        = reflect.ValueOf(&o).Elem()
+		cv        = reflect.ValueOf(&conflicts).Elem()
+	)
+	for i := 0; i < sv.NumField(); i++ {
+		if mergedKeywords[sv.Type().Field(i).Name] || ov.Field(i).IsZero() {
+			continue
+		}
+		if sv.Field(i).IsZero() {
+			sv.Field(i).Set(ov.Field(i))
+			continue
+		}
+
+		var a, b Schema
+		reflect.ValueOf(&a).Elem().Field(i).Set(sv.Field(i))
+		reflect.ValueOf(&b).Elem().Field(i).Set(ov.Field(i))
+		if !Equal(a, b) {
+			cv.Field(i).Set(ov.Field(i))
+		}
+	}
+	if !conflicts.IsTrue() {
+		s.AllOf = append(s.AllOf, conflicts)
+	}
+	return s
+}
+
+// mergedKeywords are the fields of Schema merged individually by Merge.
+var mergedKeywords = map[string]bool{
+	"Title":             true,
+	"Description":       true,
+	"Comment":           true,
+	"Default":           true,
+	"Deprecated":        true,
+	"ReadOnly":          true,
+	"WriteOnly":         true,
+	"Examples":          true,
+	"Type":              true,
+	"Required":          true,
+	"DependentRequired": true,
+	"Properties":        true,
+	"Defs":              true,
+	"AllOf":             true,
+}
+
+// mergeSchemas merges the schemas of override into base by name, base is
+// modified.
+func mergeSchemas(base, override map[string]Schema) map[string]Schema {
+	for name, o := range override {
+		if base == nil {
+			base = make(map[string]Schema, len(override))
+		}
+		if b, ok := base[name]; ok {
+			base[name] = Merge(b, o)
+		} else {
+			base[name] = o
+		}
+	}
+	return base
+}
+
+// intersectTypes returns the types allowed by both type sets, an integer is
+// allowed by number.
+func intersectTypes(a, b TypeSet) TypeSet {
+	var ts TypeSet
+	add := func(t Type) {
+		if !slices.Contains(ts, t) {
+			ts = append(ts, t)
+		}
+	}
+	for _, t := range a {
+		switch {
+		case slices.Contains(b, t):
+			add(t)
+		case t == TypeNumber && slices.Contains(b, TypeInteger),
+			t == TypeInteger && slices.Contains(b, TypeNumber):
+			add(TypeInteger)
+		}
+	}
+	return ts
+}
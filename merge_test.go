@@ -0,0 +1,130 @@
+package jsonschema_test
+
+import (
+	"encoding/json"
+	. "jsonschema"
+	"testing"
+)
+
+func TestMerge(t *testing.T) {
+	tests := map[string]struct {
+		base, override, expected Schema
+	}{
+		"annotations": {
+			base:     Schema{Title: "Order", Description: "An order.", Deprecated: ptr(false)},
+			override: Schema{Description: "A submitted order.", Deprecated: ptr(true)},
+			expected: Schema{Title: "Order", Description: "A submitted order.", Deprecated: ptr(true)},
+		},
+		"properties": {
+			base: Schema{
+				Type: TypeSet{TypeObject},
+				Properties: map[string]Schema{
+					"id":   {Type: TypeSet{TypeString}},
+					"note": {Type: TypeSet{TypeString, TypeNull}, Title: "Note"},
+				},
+				Required:             []string{"id"},
+				AdditionalProperties: &False,
+			},
+			override: Schema{
+				Properties: map[string]Schema{
+					"note":  {Type: TypeSet{TypeString}, MaxLength: ptr(100)},
+					"total": {Type: TypeSet{TypeNumber}},
+				},
+				Required:             []string{"total", "id"},
+				AdditionalProperties: &False,
+			},
+			expected: Schema{
+				Type: TypeSet{TypeObject},
+				Properties: map[string]Schema{
+					"id":    {Type: TypeSet{TypeString}},
+					"note":  {Type: TypeSet{TypeString}, Title: "Note", MaxLength: ptr(100)},
+					"total": {Type: TypeSet{TypeNumber}},
+				},
+				Required:             []string{"id", "total"},
+				AdditionalProperties: &False,
+			},
+		},
+		"conflicts": {
+			base: Schema{
+				Type:      TypeSet{TypeString, TypeNull},
+				MinLength: ptr(1),
+				Pattern:   ptr("^[a-z]+$"),
+				AllOf:     []Schema{{Format: ptr("email")}},
+			},
+			override: Schema{
+				Type:      TypeSet{TypeString},
+				MinLength: ptr(2),
+				Pattern:   ptr("^[a-z]+$"),
+				MaxLength: ptr(10),
+			},
+			expected: Schema{
+				Type:      TypeSet{TypeString},
+				MinLength: ptr(1),
+				MaxLength: ptr(10),
+				Pattern:   ptr("^[a-z]+$"),
+				AllOf:     []Schema{{Format: ptr("email")}, {MinLength: ptr(2)}},
+			},
+		},
+		"equal numbers": {
+			base:     Schema{Minimum: ptr(json.Number("1"))},
+			override: Schema{Minimum: ptr(json.Number("1.0"))},
+			expected: Schema{Minimum: ptr(json.Number("1"))},
+		},
+		"dependentRequired": {
+			base:     Schema{DependentRequired: map[string][]string{"a": {"b"}}},
+			override: Schema{DependentRequired: map[string][]string{"a": {"c", "b"}, "d": {"e"}}},
+			expected: Schema{DependentRequired: map[string][]string{"a": {"b", "c"}, "d": {"e"}}},
+		},
+		"number and integer": {
+			base:     Schema{Type: TypeSet{TypeNumber, TypeNull}},
+			override: Schema{Type: TypeSet{TypeInteger}},
+			expected: Schema{Type: TypeSet{TypeInteger}},
+		},
+		"disjoint types": {
+			base:     Schema{Type: TypeSet{TypeString}},
+			override: Schema{Type: TypeSet{TypeInteger}},
+			expected: False,
+		},
+		"false base": {
+			base:     False,
+			override: Schema{Title: "x"},
+			expected: False,
+		},
+		"false override": {
+			base:     Schema{Type: TypeSet{TypeString}},
+			override: False,
+			expected: False,
+		},
+		"true": {
+			base:     True,
+			override: Schema{Type: TypeSet{TypeString}},
+			expected: Schema{Type: TypeSet{TypeString}},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			base, override := Copy(test.base), Copy(test.override)
+			s := Merge(test.base, test.override)
+			if !Equal(s, test.expected) {
+				t.Errorf("\nhave %s\nneed %s", &s, &test.expected)
+			}
+			if !Equal(test.base, base) || !Equal(test.override, override) {
+				t.Errorf("inputs were modified")
+			}
+		})
+	}
+}
+
+func TestMerge_Independent(t *testing.T) {
+	base := Schema{Properties: map[string]Schema{"a": {Enum: []any{"x"}}}}
+	override := Schema{Properties: map[string]Schema{"b": {Enum: []any{"y"}}}}
+
+	s := Merge(base, override)
+	s.Properties["a"].Enum[0] = "changed"
+	s.Properties["b"].Enum[0] = "changed"
+
+	if base.Properties["a"].Enum[0] != "x" || override.Properties["b"].Enum[0] != "y" {
+		t.Errorf("merged schema shares values with its inputs")
+	}
+}
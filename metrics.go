@@ -0,0 +1,34 @@
+package jsonschema
+
+// Metrics describes the size and complexity of a schema tree.
+type Metrics struct {
+	Subschemas int // Number of subschemas, excluding the root schema.
+	MaxDepth   int // Maximum nesting depth, the root schema has a depth of 0.
+	Refs       int // Number of schemas with a $ref keyword.
+	Defs       int // Number of $defs entries, across all schemas.
+}
+
+// Complexity computes the Metrics of the schema tree rooted at root. References
+// are not resolved, so every schema is visited exactly once. This can be used
+// to reject overly large or deeply nested schemas before processing them.
+func Complexity(root *Schema) Metrics {
+	var (
+		m    Metrics
+		walk func(*Schema, int)
+	)
+	walk = func(s *Schema, depth int) {
+		m.MaxDepth = max(m.MaxDepth, depth)
+		m.Defs += len(s.Defs)
+		if s.Ref != "" {
+			m.Refs++
+		}
+
+		iter(s, func(_ string, schema *Schema) bool {
+			m.Subschemas++
+			walk(schema, depth+1)
+			return true
+		})
+	}
+	walk(root, 0)
+	return m
+}
@@ -0,0 +1,41 @@
+package jsonschema_test
+
+import (
+	"encoding/json"
+	. "jsonschema"
+	"testing"
+)
+
+func TestComplexity(t *testing.T) {
+	const p = `{
+  "$ref": "#/$defs/a",
+  "$defs": {
+    "a": {"items": {"$ref": "#/$defs/b"}},
+    "b": {"$defs": {"c": true}}
+  },
+  "properties": {
+    "foo": {"allOf": [{"not": {"$ref": "#/$defs/a"}}, true]}
+  }
+}`
+
+	var s Schema
+	if err := json.Unmarshal([]byte(p), &s); err != nil {
+		t.Logf("unexpected error: %s", err)
+		t.FailNow()
+	}
+
+	tests := []struct {
+		schema  *Schema
+		metrics Metrics
+	}{
+		{schema: &Schema{}, metrics: Metrics{}},
+		{schema: &False, metrics: Metrics{Subschemas: 1, MaxDepth: 1}},
+		{schema: &s, metrics: Metrics{Subschemas: 8, MaxDepth: 3, Refs: 3, Defs: 3}},
+	}
+
+	for i, test := range tests {
+		if m := Complexity(test.schema); m != test.metrics {
+			t.Errorf("test #%d: have %+v, need %+v", i, m, test.metrics)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"strings"
+)
+
+// CanonicalizeNumbers rewrites all numbers of s and its subschemas to their
+// canonical representation, so that equal numbers have equal representations.
+// This includes the numeric keywords and json.Number values within const and
+// enum. Numbers that cannot be parsed are left unchanged.
+//
+//	1.0    // 1
+//	-0     // 0
+//	1.50   // 1.5
+//	1e3    // 1000
+//	2.5E-1 // 0.25
+func CanonicalizeNumbers(s *Schema) {
+	_ = Walk(s, func(_ string, schema *Schema) error {
+		for _, n := range []*json.Number{
+			schema.MultipleOf,
+			schema.Maximum,
+			schema.ExclusiveMaximum,
+			schema.Minimum,
+			schema.ExclusiveMinimum,
+		} {
+			if n != nil {
+				*n = canonicalNumber(*n)
+			}
+		}
+
+		schema.Const = canonicalizeValue(schema.Const)
+		for i := range schema.Enum {
+			schema.Enum[i] = canonicalizeValue(schema.Enum[i])
+		}
+		return nil
+	})
+}
+
+// canonicalizeValue returns v with all json.Number values being canonicalized.
+// Slices and maps are modified in place.
+func canonicalizeValue(v any) any {
+	switch v := v.(type) {
+	case json.Number:
+		return canonicalNumber(v)
+	case []any:
+		for i := range v {
+			v[i] = canonicalizeValue(v[i])
+		}
+	case map[string]any:
+		for k := range v {
+			v[k] = canonicalizeValue(v[k])
+		}
+	}
+	return v
+}
+
+// canonicalJSON returns the JSON encoding of v with canonical numbers and sorted
+// object keys, so that equal JSON values have equal encodings.
+func canonicalJSON(v any) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	if err = d.Decode(&v); err != nil {
+		return "", err
+	}
+
+	b, err = json.Marshal(canonicalizeValue(v))
+	return string(b), err
+}
+
+// canonicalNumber returns the shortest exact decimal representation of n, without
+// an exponent. n is returned unchanged if it is not a valid number.
+func canonicalNumber(n json.Number) json.Number {
+	r, ok := new(big.Rat).SetString(string(n))
+	if !ok {
+		return n
+	}
+
+	if r.IsInt() {
+		return json.Number(r.Num().String())
+	}
+
+	// The denominator of a parsed decimal number is a product of powers of two
+	// and five, the larger exponent is the number of required decimal places.
+	var (
+		d, q, mod = new(big.Int).Set(r.Denom()), new(big.Int), new(big.Int)
+		places    int
+	)
+	for _, p := range []int64{2, 5} {
+		var exp int
+		for bp := big.NewInt(p); ; exp++ {
+			if q.QuoRem(d, bp, mod); mod.Sign() != 0 {
+				break
+			}
+			d.Set(q)
+		}
+		places = max(places, exp)
+	}
+
+	str := r.FloatString(places)
+	if strings.Contains(str, ".") {
+		str = strings.TrimRight(strings.TrimRight(str, "0"), ".")
+	}
+	return json.Number(str)
+}
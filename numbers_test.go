@@ -0,0 +1,51 @@
+package jsonschema_test
+
+import (
+	"encoding/json"
+	. "jsonschema"
+	"reflect"
+	"testing"
+)
+
+func TestCanonicalizeNumbers(t *testing.T) {
+	s := Schema{
+		Minimum:          ptr(json.Number("1.0")),
+		Maximum:          ptr(json.Number("1e3")),
+		ExclusiveMinimum: ptr(json.Number("-0")),
+		ExclusiveMaximum: ptr(json.Number("2.5E-1")),
+		MultipleOf:       ptr(json.Number("0.10")),
+		Properties: map[string]Schema{
+			"foo": {
+				Minimum: ptr(json.Number("-001.500")),
+				Const:   []any{json.Number("3.0"), map[string]any{"a": json.Number("-0.0")}},
+			},
+		},
+		Items: &Schema{
+			Maximum: ptr(json.Number("invalid")),
+			Enum:    []any{json.Number("10.00"), "10.00", 10.5},
+		},
+	}
+
+	expected := Schema{
+		Minimum:          ptr(json.Number("1")),
+		Maximum:          ptr(json.Number("1000")),
+		ExclusiveMinimum: ptr(json.Number("0")),
+		ExclusiveMaximum: ptr(json.Number("0.25")),
+		MultipleOf:       ptr(json.Number("0.1")),
+		Properties: map[string]Schema{
+			"foo": {
+				Minimum: ptr(json.Number("-1.5")),
+				Const:   []any{json.Number("3"), map[string]any{"a": json.Number("0")}},
+			},
+		},
+		Items: &Schema{
+			Maximum: ptr(json.Number("invalid")),
+			Enum:    []any{json.Number("10"), "10.00", 10.5},
+		},
+	}
+
+	CanonicalizeNumbers(&s)
+	if !reflect.DeepEqual(s, expected) {
+		t.Errorf("\nhave %s\nneed %s", &s, &expected)
+	}
+}
@@ -0,0 +1,110 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// Position is the location of a JSON value in a schema document.
+type Position struct {
+	// Offset is the byte offset of the first byte of the value.
+	Offset int
+	// Line and Column are 1-based, the column is counted in bytes.
+	Line, Column int
+}
+
+func (p Position) String() string {
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
+// PositionIndex maps the JSON pointers of the values of a schema document to their
+// position in the document. The pointers are escaped according to RFC 6901, the
+// pointer of the root value is the empty string.
+type PositionIndex map[string]Position
+
+// Lookup returns the position of the value ptr points to. Like the pointers passed
+// to a WalkFunc, ptr may be "/" to denote the root value.
+func (idx PositionIndex) Lookup(ptr string) (Position, bool) {
+	if ptr == "/" {
+		ptr = ""
+	}
+	p, ok := idx[ptr]
+	return p, ok
+}
+
+// UnmarshalWithPositions parses the schema document b and records the position of
+// every value of the document, including every subschema, by its JSON pointer.
+// This allows to map a pointer reported for a schema, e.g. by Lint, back to the
+// source.
+func UnmarshalWithPositions(b []byte) (*Schema, PositionIndex, error) {
+	s := &Schema{}
+	if err := json.Unmarshal(b, s); err != nil {
+		return nil, nil, err
+	}
+
+	// lines contains the offsets of the first byte of every line.
+	lines := []int{0}
+	for i, c := range b {
+		if c == '\n' {
+			lines = append(lines, i+1)
+		}
+	}
+
+	p := positionParser{b: b, d: json.NewDecoder(bytes.NewReader(b)), lines: lines, idx: make(PositionIndex)}
+	if err := p.value(""); err != nil {
+		return nil, nil, fmt.Errorf("failed to record positions: %w", err)
+	}
+	return s, p.idx, nil
+}
+
+type positionParser struct {
+	b     []byte
+	d     *json.Decoder
+	lines []int
+	idx   PositionIndex
+}
+
+// value records the position of the next value and its members by ptr.
+func (p *positionParser) value(ptr string) error {
+	// The input offset is located after the previous token, which may be followed
+	// by whitespace and a separator.
+	off := int(p.d.InputOffset())
+	for off < len(p.b) && bytes.IndexByte([]byte(" \t\r\n,:"), p.b[off]) >= 0 {
+		off++
+	}
+	line := sort.Search(len(p.lines), func(i int) bool { return p.lines[i] > off })
+	p.idx[ptr] = Position{Offset: off, Line: line, Column: off - p.lines[line-1] + 1}
+
+	tok, err := p.d.Token()
+	if err != nil {
+		return err
+	}
+
+	switch tok {
+	case json.Delim('{'):
+		for p.d.More() {
+			key, err := p.d.Token()
+			if err != nil {
+				return err
+			}
+			if err = p.value(ptr + "/" + escapeToken(key.(string))); err != nil {
+				return err
+			}
+		}
+	case json.Delim('['):
+		for i := 0; p.d.More(); i++ {
+			if err = p.value(ptr + "/" + strconv.Itoa(i)); err != nil {
+				return err
+			}
+		}
+	default:
+		return nil
+	}
+
+	// consume the closing delimiter
+	_, err = p.d.Token()
+	return err
+}
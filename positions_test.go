@@ -0,0 +1,65 @@
+package jsonschema_test
+
+import (
+	. "jsonschema"
+	"reflect"
+	"testing"
+)
+
+func TestUnmarshalWithPositions(t *testing.T) {
+	doc := []byte(`{
+  "type": "object",
+  "properties": {
+    "a/b": {"type": "string"},
+    "list": {
+      "prefixItems": [true, {"minimum": 1}]
+    }
+  }
+}`)
+
+	s, idx, err := UnmarshalWithPositions(doc)
+	if err != nil {
+		t.Logf("unexpected error: %s", err)
+		t.FailNow()
+	}
+
+	if _, ok := s.Properties["a/b"]; !ok {
+		t.Errorf("expected parsed schema, have %s", s)
+	}
+
+	tests := map[string]Position{
+		"":                                    {Offset: 0, Line: 1, Column: 1},
+		"/type":                               {Offset: 12, Line: 2, Column: 11},
+		"/properties":                         {Offset: 38, Line: 3, Column: 17},
+		"/properties/a~1b":                    {Offset: 51, Line: 4, Column: 12},
+		"/properties/a~1b/type":               {Offset: 60, Line: 4, Column: 21},
+		"/properties/list/prefixItems/0":      {Offset: 107, Line: 6, Column: 23},
+		"/properties/list/prefixItems/1":      {Offset: 113, Line: 6, Column: 29},
+		"/properties/list/prefixItems/1/type": {},
+	}
+
+	for ptr, expected := range tests {
+		p, ok := idx.Lookup(ptr)
+		if expected == (Position{}) {
+			if ok {
+				t.Errorf("%q: unexpected position %s", ptr, p)
+			}
+			continue
+		}
+		if !reflect.DeepEqual(p, expected) {
+			t.Errorf("%q: have %+v, need %+v", ptr, p, expected)
+		}
+	}
+
+	if p, _ := idx.Lookup("/"); p != idx[""] {
+		t.Errorf("expected / to denote the root, have %+v", p)
+	}
+}
+
+func TestUnmarshalWithPositions_Invalid(t *testing.T) {
+	for _, doc := range []string{`{"type": `, `{"type": 12}`, ``} {
+		if _, _, err := UnmarshalWithPositions([]byte(doc)); err == nil {
+			t.Errorf("%q: expected error", doc)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package jsonschema
+
+import "slices"
+
+// ProjectMode selects the properties kept by Project.
+type ProjectMode int
+
+const (
+	// ReadMode removes all writeOnly properties, e.g. for a response schema.
+	ReadMode ProjectMode = iota
+	// WriteMode removes all readOnly properties, e.g. for a request schema.
+	WriteMode
+)
+
+// Project returns a copy of s without the properties that are not used in the
+// given mode. Removed properties are also removed from required and
+// dependentRequired. All subschemas, including $defs, are projected.
+//
+// Only properties that are marked as readOnly or writeOnly themselves are removed,
+// references are not resolved.
+func Project(s Schema, mode ProjectMode) Schema {
+	c := Copy(s)
+	_ = Walk(&c, func(_ string, schema *Schema) error {
+		var removed []string
+		for name, prop := range schema.Properties {
+			flag := prop.WriteOnly
+			if mode == WriteMode {
+				flag = prop.ReadOnly
+			}
+
+			if flag != nil && *flag {
+				delete(schema.Properties, name)
+				removed = append(removed, name)
+			}
+		}
+
+		if len(removed) == 0 {
+			return nil
+		}
+
+		isRemoved := func(name string) bool {
+			return slices.Contains(removed, name)
+		}
+
+		schema.Required = slices.DeleteFunc(schema.Required, isRemoved)
+		for name, deps := range schema.DependentRequired {
+			if isRemoved(name) {
+				delete(schema.DependentRequired, name)
+			} else {
+				schema.DependentRequired[name] = slices.DeleteFunc(deps, isRemoved)
+			}
+		}
+		return nil
+	})
+	return c
+}
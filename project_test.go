@@ -0,0 +1,77 @@
+package jsonschema_test
+
+import (
+	. "jsonschema"
+	"reflect"
+	"testing"
+)
+
+func TestProject(t *testing.T) {
+	s := Schema{
+		Properties: map[string]Schema{
+			"id":       {Type: TypeSet{TypeString}, ReadOnly: ptr(true)},
+			"name":     {Type: TypeSet{TypeString}},
+			"password": {Type: TypeSet{TypeString}, WriteOnly: ptr(true)},
+			"address":  {Ref: "#/$defs/address"},
+		},
+		Required: []string{"id", "name", "password"},
+		DependentRequired: map[string][]string{
+			"name": {"id", "password"},
+		},
+		Defs: map[string]Schema{
+			"address": {
+				Properties: map[string]Schema{
+					"street":   {Type: TypeSet{TypeString}, ReadOnly: ptr(false)},
+					"verified": {Type: TypeSet{TypeBoolean}, ReadOnly: ptr(true)},
+				},
+				Required: []string{"street", "verified"},
+			},
+		},
+	}
+	original := Copy(s)
+
+	read := Schema{
+		Properties: map[string]Schema{
+			"id":      {Type: TypeSet{TypeString}, ReadOnly: ptr(true)},
+			"name":    {Type: TypeSet{TypeString}},
+			"address": {Ref: "#/$defs/address"},
+		},
+		Required: []string{"id", "name"},
+		DependentRequired: map[string][]string{
+			"name": {"id"},
+		},
+		Defs: original.Defs,
+	}
+
+	write := Schema{
+		Properties: map[string]Schema{
+			"name":     {Type: TypeSet{TypeString}},
+			"password": {Type: TypeSet{TypeString}, WriteOnly: ptr(true)},
+			"address":  {Ref: "#/$defs/address"},
+		},
+		Required: []string{"name", "password"},
+		DependentRequired: map[string][]string{
+			"name": {"password"},
+		},
+		Defs: map[string]Schema{
+			"address": {
+				Properties: map[string]Schema{
+					"street": {Type: TypeSet{TypeString}, ReadOnly: ptr(false)},
+				},
+				Required: []string{"street"},
+			},
+		},
+	}
+
+	if p := Project(s, ReadMode); !reflect.DeepEqual(p, read) {
+		t.Errorf("\nhave %s\nneed %s", &p, &read)
+	}
+
+	if p := Project(s, WriteMode); !reflect.DeepEqual(p, write) {
+		t.Errorf("\nhave %s\nneed %s", &p, &write)
+	}
+
+	if !reflect.DeepEqual(s, original) {
+		t.Errorf("schema was modified")
+	}
+}
@@ -3,6 +3,7 @@ package jsonschema
 import (
 	"errors"
 	"jsonschema/jsonptr"
+	"strings"
 )
 
 // ErrPtrUnknownKeyword is a sentinel error indicating that an unknown keyword was
@@ -27,6 +28,12 @@ func ValidateReferencePointer(ref string) error {
 	return jsonptr.ValidateJSONPointerFunc(ref, schemaSegmentValidator)
 }
 
+// escapeToken escapes a JSON pointer reference token according to RFC 6901.
+func escapeToken(token string) string {
+	token = strings.ReplaceAll(token, "~", "~0")
+	return strings.ReplaceAll(token, "/", "~1")
+}
+
 func isNCName(str string) bool {
 	r := []rune(str)
 	for i := 0; i < len(r); i++ {
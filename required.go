@@ -0,0 +1,27 @@
+package jsonschema
+
+// RequiredPaths returns the required property names of every schema in the tree
+// rooted at root, including $defs and conditional subschemas. The map key is the
+// JSON pointer to the schema defining the required properties. Schemas without
+// required properties are not included.
+//
+//	{
+//	  "required": ["name"],
+//	  "properties": {
+//	    "address": {"required": ["street"]}
+//	  }
+//	}
+//
+// results in
+//
+//	{"/": ["name"], "/properties/address": ["street"]}
+func RequiredPaths(root *Schema) map[string][]string {
+	m := make(map[string][]string)
+	_ = Walk(root, func(ptr string, schema *Schema) error {
+		if len(schema.Required) > 0 {
+			m[ptr] = copySlice(schema.Required)
+		}
+		return nil
+	})
+	return m
+}
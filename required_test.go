@@ -0,0 +1,42 @@
+package jsonschema_test
+
+import (
+	. "jsonschema"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestRequiredPaths(t *testing.T) {
+	loader := NewEmbeddedLoader(testdataFS)
+
+	uri, _ := url.Parse("file:///testdata/miscellaneous-examples/conditional-validation-if-else.schema.json")
+	schema, _ := loader.Load(nil, uri)
+
+	expected := map[string][]string{
+		"/": {"isMember"},
+	}
+	if m := RequiredPaths(schema); !reflect.DeepEqual(m, expected) {
+		t.Errorf("have %v, need %v", m, expected)
+	}
+
+	schema = &Schema{
+		Required: []string{"name"},
+		Properties: map[string]Schema{
+			"address": {Required: []string{"street", "city"}},
+		},
+		Defs: map[string]Schema{
+			"foo": {Required: []string{"bar"}},
+		},
+		AllOf: []Schema{{Required: []string{}}},
+	}
+
+	expected = map[string][]string{
+		"/":                   {"name"},
+		"/properties/address": {"street", "city"},
+		"/$defs/foo":          {"bar"},
+	}
+	if m := RequiredPaths(schema); !reflect.DeepEqual(m, expected) {
+		t.Errorf("have %v, need %v", m, expected)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"slices"
 	"strconv"
 	"strings"
 )
@@ -12,12 +13,44 @@ type ResolveConfig struct {
 	Context context.Context
 	Loader  Loader
 
+	// BaseURI is the URI the resource was retrieved from, relative references of
+	// the resource and its $id are resolved against it. It is required to resolve
+	// the references of a schema returned by ResolveReferenceBase, which may be
+	// located in a document without $id.
+	BaseURI *url.URL
+
 	resource            *Schema
 	rootResource        *Schema
 	rootResourceLoader  Loader
 	resourceURI         *url.URL
 	computedIdentifiers map[string]Identifiers
 	ignoreRefs          bool
+
+	// keepRefs returns the referenced schema even if it is a reference itself,
+	// instead of following it. Used by Compile, which resolves every reference
+	// on its own.
+	keepRefs bool
+
+	// dynamicScope contains the resources entered during the resolution, the
+	// outermost first, see resolveDynamicRef.
+	dynamicScope []scopeEntry
+}
+
+// scopeEntry is a resource of the dynamic scope.
+type scopeEntry struct {
+	resource *Schema
+	uri      *url.URL
+}
+
+// enterResource adds the current resource to the dynamic scope, unless it is
+// the innermost resource already.
+func (c *ResolveConfig) enterResource() {
+	if n := len(c.dynamicScope); n > 0 && c.dynamicScope[n-1].resource == c.resource {
+		return
+	}
+	// The scope is shared with the configs of the enclosing resources, which
+	// must not see the entry.
+	c.dynamicScope = append(slices.Clip(c.dynamicScope), scopeEntry{resource: c.resource, uri: c.resourceURI})
 }
 
 func applyDefaults(config *ResolveConfig, resource *Schema) {
@@ -35,38 +68,156 @@ func applyDefaults(config *ResolveConfig, resource *Schema) {
 		config.resource = resource
 	}
 
-	if config.resourceURI == nil {
+	if config.resourceURI == nil && config.BaseURI != nil {
+		// The $id of the resource is resolved against the base URI by the caller.
+		u := *config.BaseURI
+		config.resourceURI = &u
+	} else if config.resourceURI == nil {
 		config.resourceURI, _ = url.Parse(resource.ID)
 	}
 
 	if config.rootResource == nil {
+		root := withAbsoluteID(resource, config.BaseURI)
 		config.rootResource = resource
-		config.rootResourceLoader = NewLocalLoader(resource, nil)
-		config.computedIdentifiers, _ = ComputeIdentifiers(*resource)
+		config.rootResourceLoader = NewLocalLoader(root, nil)
+		config.computedIdentifiers, _ = ComputeIdentifiers(*root)
+	}
+}
+
+// withAbsoluteID returns resource with its $id resolved against base, so that the
+// identifiers of embedded resources are absolute. The resource is returned as is
+// if it has no relative $id or base is nil.
+func withAbsoluteID(resource *Schema, base *url.URL) *Schema {
+	id, err := url.Parse(resource.ID)
+	if resource.ID == "" || base == nil || err != nil || id.IsAbs() {
+		return resource
 	}
+	abs := *resource
+	abs.ID = base.ResolveReference(id).String()
+	return &abs
 }
 
 // ResolveReference resolves a JSON reference pointer against the provided Schema.
 // If the reference (or some node of it) points to an external URI, the loaders is
 // used.
+//
+// If the reference cannot be resolved, a *ResolveError is returned.
 func ResolveReference(config ResolveConfig, ref string, resource *Schema) (*Schema, error) {
+	s, _, err := ResolveReferenceBase(config, ref, resource)
+	return s, err
+}
+
+// ResolveReferenceBase resolves the reference like ResolveReference and also
+// returns the base URI of the resolved schema, i.e. the URI of the resource the
+// schema is located in. Use it as ResolveConfig.BaseURI to resolve a relative
+// reference found in the schema:
+//
+//	s, base, err := ResolveReferenceBase(config, "a.json#/$defs/x", root)
+//	// s is {"properties": {"y": {"$ref": "b.json"}}}, b.json is relative to a.json
+//	y := s.Properties["y"]
+//	b, err := ResolveReference(ResolveConfig{Loader: loader, BaseURI: base}, y.Ref, &y)
+//
+// A reference only consisting of a JSON pointer fragment is resolved against the
+// resource passed to ResolveReference, which should be the root of the resource.
+func ResolveReferenceBase(config ResolveConfig, ref string, resource *Schema) (*Schema, *url.URL, error) {
+	s, c, err := resolveReference(config, ref, resource)
+	if err != nil {
+		if _, ok := err.(*ResolveError); !ok {
+			err = &ResolveError{URI: absoluteRef(c, ref), Err: err}
+		}
+		return nil, nil, err
+	}
+	base := *c.resourceURI
+	if s.ID != "" && s != c.resource {
+		// The resolved schema is a resource of its own, e.g. if a JSON pointer
+		// points to it.
+		id, _ := url.Parse(s.ID)
+		base = *base.ResolveReference(id)
+	}
+	base.Fragment = ""
+	return s, &base, nil
+}
+
+// ResolveError is returned if a reference cannot be resolved. Resolving a
+// reference may require following further references, e.g. if a schema on the
+// path of a JSON pointer is a reference itself. These references are recorded
+// as hops, the last hop is the reference that failed.
+type ResolveError struct {
+	// Hops contains the references followed, in order.
+	Hops []RefHop
+	// URI is the absolute URI of the reference that failed.
+	URI string
+	// Err is the cause of the failure.
+	Err error
+}
+
+// RefHop is a reference followed while resolving another reference.
+type RefHop struct {
+	Ref      string // The value of the $ref keyword.
+	Location string // The URI of the schema containing the $ref keyword.
+}
+
+func (e *ResolveError) Error() string {
+	var sb strings.Builder
+	for _, hop := range e.Hops {
+		fmt.Fprintf(&sb, "failed to resolve {\"$ref\": %q} at %q: ", hop.Ref, hop.Location)
+	}
+	sb.WriteString(e.Err.Error())
+	return sb.String()
+}
+
+func (e *ResolveError) Unwrap() error {
+	return e.Err
+}
+
+// withHop returns err with the hop prepended, err is wrapped in a ResolveError
+// if it is not one already. The hop was followed using config.
+func withHop(err error, config ResolveConfig, hop RefHop) error {
+	re, ok := err.(*ResolveError)
+	if !ok {
+		re = &ResolveError{URI: absoluteRef(config, hop.Ref), Err: err}
+	}
+	re.Hops = append([]RefHop{hop}, re.Hops...)
+	return re
+}
+
+// absoluteRef returns ref resolved against the resource URI of config.
+func absoluteRef(config ResolveConfig, ref string) string {
+	u, err := url.Parse(ref)
+	if err != nil || config.resourceURI == nil {
+		return ref
+	}
+	return config.resourceURI.ResolveReference(u).String()
+}
+
+// resolveReference resolves the reference like ResolveReference, but also returns
+// the config describing the resource the resolved schema is located in.
+func resolveReference(config ResolveConfig, ref string, resource *Schema) (*Schema, ResolveConfig, error) {
 	applyDefaults(&config, resource)
 
 	if resource.ID != "" {
 		config.resource = resource
 
+		base := config.resourceURI
 		uri, _ := url.Parse(resource.ID)
-		config.resourceURI = config.resourceURI.ResolveReference(uri)
+		config.resourceURI = base.ResolveReference(uri)
 
 		// If ids are not computed or the resource ID is not embedded in the root
 		// schema resource!
 		if config.computedIdentifiers == nil || !isEmbedded(resource.ID, config.computedIdentifiers) {
-			config.computedIdentifiers, _ = ComputeIdentifiers(*resource)
+			config.computedIdentifiers, _ = ComputeIdentifiers(*withAbsoluteID(resource, base))
 		}
 	}
+	config.enterResource()
 
 	uri, _ := url.Parse(ref)
-	isPointerReference := len(ref) == 0 || len(ref) > 2 && ref[0] == '#' && ref[1] == '/'
+	isPointerReference := ref == "" || ref == "#" || strings.HasPrefix(ref, "#/")
+	if !isPointerReference && (uri.Fragment == "" || uri.Fragment[0] == '/') {
+		// The reference may still point into the current resource, e.g. if it
+		// uses the absolute URI of the resource.
+		uri = config.resourceURI.ResolveReference(uri)
+		isPointerReference = sameResource(uri, config.resourceURI)
+	}
 
 	var path []string
 	if isPointerReference {
@@ -79,7 +230,7 @@ func ResolveReference(config ResolveConfig, ref string, resource *Schema) (*Sche
 			bURI, _ := url.Parse(uri.String())
 			bURI.Fragment = ""
 			for _, id := range config.computedIdentifiers {
-				if id.BaseURI == uri.String() {
+				if id.BaseURI == bURI.String() {
 					ids = id
 					break
 				}
@@ -87,18 +238,29 @@ func ResolveReference(config ResolveConfig, ref string, resource *Schema) (*Sche
 
 			s, err := config.rootResourceLoader.Load(config.Context, uri)
 			if err != nil {
-				return nil, fmt.Errorf("unable to locate embedded resource: %w", err)
+				return nil, config, fmt.Errorf("unable to locate embedded resource: %w", err)
 			}
 
 			resource = s
 			config.resource = s
 			config.resourceURI, _ = url.Parse(ids.BaseURI)
+			config.enterResource()
 		} else {
+			// A loaded schema without an $id is identified by the URI it was
+			// retrieved from, its relative references are resolved against it.
+			retrieval := *uri
+			retrieval.Fragment, retrieval.RawFragment = "", ""
+
 			s, err := config.Loader.Load(config.Context, uri)
 			if err != nil {
-				return nil, fmt.Errorf("unable to locate non-embedded resource {\"$id\": %q}: %w", uri, err)
+				return nil, config, fmt.Errorf("unable to locate non-embedded resource {\"$id\": %q}: %w", uri, err)
 			}
-			return ResolveReference(ResolveConfig{Context: config.Context, Loader: config.Loader}, uri.String(), s)
+
+			next := ResolveConfig{Context: config.Context, Loader: config.Loader, dynamicScope: config.dynamicScope, keepRefs: config.keepRefs}
+			if s.ID == "" {
+				next.resourceURI = &retrieval
+			}
+			return resolveReference(next, uri.String(), s)
 		}
 
 		if uri.Path != "" {
@@ -112,6 +274,87 @@ func ResolveReference(config ResolveConfig, ref string, resource *Schema) (*Sche
 	return resolveRef(config, config.resource, path, 0)
 }
 
+// ResolveAll returns a copy of root with every $ref replaced by the schema it
+// references, using loader for external references. Keywords next to a replaced
+// $ref are kept, in which case the referenced schema is added to allOf.
+//
+// References that are part of a cycle cannot be replaced and are kept as they
+// are, or as absolute URIs if they are relative to a different resource.
+func ResolveAll(ctx context.Context, root *Schema, loader Loader) (*Schema, error) {
+	c := Copy(*root)
+	config := ResolveConfig{Context: ctx, Loader: loader}
+	applyDefaults(&config, &c)
+
+	rootURI := config.resourceURI
+
+	// The visiting list contains the absolute URIs of all schemas on the current
+	// path, including referenced schemas, a reference to any of them is a cycle.
+	var inline func(ResolveConfig, *Schema, string, []string) error
+	inline = func(config ResolveConfig, s *Schema, ptr string, visiting []string) error {
+		if s.ID != "" {
+			uri, _ := url.Parse(s.ID)
+			config.resource = s
+			config.resourceURI = config.resourceURI.ResolveReference(uri)
+			ptr = ""
+		}
+
+		loc := *config.resourceURI
+		loc.Fragment = ptr
+		visiting = append(visiting, loc.String())
+
+		var err error
+		iter(s, func(p string, schema *Schema) bool {
+			err = inline(config, schema, ptr+"/"+p, visiting)
+			return err == nil
+		})
+		if err != nil || s.Ref == "" {
+			return err
+		}
+
+		ref, _ := url.Parse(s.Ref)
+		key := config.resourceURI.ResolveReference(ref).String()
+		if slices.Contains(visiting, key) {
+			if !sameResource(config.resourceURI, rootURI) {
+				s.Ref = key
+			}
+			return nil
+		}
+
+		target, tc, err := resolveReference(config, s.Ref, s)
+		if err != nil {
+			return fmt.Errorf("failed to resolve {\"$ref\": %q}: %w", s.Ref, err)
+		}
+
+		// The location of the referenced schema within its resource is unknown,
+		// the reference itself is used to detect cycles instead.
+		t := Copy(*target)
+		if err = inline(tc, &t, "", append(visiting, key)); err != nil {
+			return err
+		}
+
+		if s.Ref = ""; s.IsTrue() {
+			*s = t
+		} else {
+			s.AllOf = append(s.AllOf, t)
+		}
+		return nil
+	}
+
+	if err := inline(config, &c, "", nil); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// sameResource returns whether both URIs point to the same resource, ignoring
+// their fragments.
+func sameResource(a, b *url.URL) bool {
+	a2, b2 := *a, *b
+	a2.Fragment, a2.RawFragment = "", ""
+	b2.Fragment, b2.RawFragment = "", ""
+	return a2.String() == b2.String()
+}
+
 func fmtPos(config ResolveConfig, path []string, pos int) string {
 	var res string
 	if uriStr := config.resourceURI.String(); uriStr != "" {
@@ -127,7 +370,7 @@ func fmtPtrPosition(path []string, pos int) string {
 	var sb strings.Builder
 	sb.WriteString("#/")
 	for i := 0; i < pos; i++ {
-		sb.WriteString(path[i])
+		sb.WriteString(escapeToken(path[i]))
 		if i < pos-1 {
 			sb.WriteString("/")
 		}
@@ -135,29 +378,43 @@ func fmtPtrPosition(path []string, pos int) string {
 	return sb.String()
 }
 
-func resolveRef(config ResolveConfig, current *Schema, path []string, pos int) (*Schema, error) {
+func resolveRef(config ResolveConfig, current *Schema, path []string, pos int) (*Schema, ResolveConfig, error) {
 	// Return if the current schema is not set, or we reached the end of
 	// the reference path without the schema having a reference itself.
-	if current == nil || (len(path[pos:]) == 0 && current.Ref == "") {
-		return current, nil
+	if current == nil || (len(path[pos:]) == 0 && current.Ref == "" && current.DynamicRef == "") {
+		return current, config, nil
 	}
 
-	if current.ID != "" {
+	// The $id of the current resource is already applied to config.
+	if current.ID != "" && current != config.resource {
 		uri, _ := url.Parse(current.ID)
 		config.resource = current
-		config.resourceURI = config.resourceURI.ResolveReference(uri)
+		if config.resourceURI == nil {
+			config.resourceURI = uri
+		} else {
+			config.resourceURI = config.resourceURI.ResolveReference(uri)
+		}
+		config.enterResource()
 	}
 
-	if current.Ref != "" /* && schema.Ref != "#" */ && (!config.ignoreRefs && len(path[pos:]) == 0) {
-		var err error
+	if current.Ref != "" && (!config.ignoreRefs && !config.keepRefs && len(path[pos:]) == 0) {
 		r := current.Ref
-		if current, err = ResolveReference(config, current.Ref, current); err != nil {
-			return nil, fmt.Errorf("failed to resolve {\"$ref\": %q} at %q: %w", r, fmtPos(config, path, pos), err)
+		s, c, err := resolveReference(config, current.Ref, current)
+		if err != nil {
+			return nil, config, withHop(err, config, RefHop{Ref: r, Location: fmtPos(config, path, pos)})
+		}
+		current, config = s, c
+	} else if current.DynamicRef != "" && (!config.ignoreRefs && !config.keepRefs && len(path[pos:]) == 0) {
+		r := current.DynamicRef
+		s, c, err := resolveDynamicRef(config, current)
+		if err != nil {
+			return nil, config, withHop(err, config, RefHop{Ref: r, Location: fmtPos(config, path, pos)})
 		}
+		current, config = s, c
 	}
 
 	if len(path[pos:]) == 0 {
-		return current, nil
+		return current, config, nil
 	}
 
 	config.ignoreRefs = false
@@ -165,7 +422,7 @@ func resolveRef(config ResolveConfig, current *Schema, path []string, pos int) (
 	switch segment {
 	case "allOf", "anyOf", "oneOf", "prefixItems":
 		if len(path[pos:]) == 1 {
-			return nil, fmt.Errorf("missing array index at %q", fmtPos(config, path, pos+1))
+			return nil, config, fmt.Errorf("missing array index at %q", fmtPos(config, path, pos+1))
 		}
 
 		nextSegment := path[pos+1]
@@ -184,15 +441,15 @@ func resolveRef(config ResolveConfig, current *Schema, path []string, pos int) (
 
 		i, err := strconv.Atoi(nextSegment)
 		if err != nil {
-			return nil, fmt.Errorf("invalid array index %q at %q: %w", nextSegment, fmtPos(config, path, pos+1), err)
+			return nil, config, fmt.Errorf("invalid array index %q at %q: %w", nextSegment, fmtPos(config, path, pos+1), err)
 		} else if len(col) <= i {
-			return nil, fmt.Errorf("index out of bounds (%d/%d) at %q", i, len(col)-1, fmtPos(config, path, pos+1))
+			return nil, config, fmt.Errorf("index out of bounds (%d/%d) at %q", i, len(col)-1, fmtPos(config, path, pos+1))
 		}
 
 		return resolveRef(config, &col[i], path, pos+2)
 	case "$defs", "dependentSchemas", "properties", "patternProperties":
 		if len(path[pos:]) == 1 {
-			return nil, fmt.Errorf("missing key at %q", fmtPos(config, path, pos+1))
+			return nil, config, fmt.Errorf("missing key at %q", fmtPos(config, path, pos+1))
 		}
 
 		var col map[string]Schema
@@ -212,7 +469,7 @@ func resolveRef(config ResolveConfig, current *Schema, path []string, pos int) (
 			ok bool
 		)
 		if s, ok = col[path[pos+1]]; !ok {
-			return nil, fmt.Errorf("unknown key %q at %q", path[pos+1], fmtPos(config, path, pos+1))
+			return nil, config, fmt.Errorf("unknown key %q at %q", path[pos+1], fmtPos(config, path, pos+1))
 		}
 
 		current = &s
@@ -245,11 +502,67 @@ func resolveRef(config ResolveConfig, current *Schema, path []string, pos int) (
 		}
 
 		if s == nil {
-			return nil, fmt.Errorf("missing schema at %q", fmtPos(config, path, pos+1))
+			return nil, config, fmt.Errorf("missing schema at %q", fmtPos(config, path, pos+1))
 		}
 		return resolveRef(config, s, path, pos+1)
 	}
-	return nil, fmt.Errorf("unknown keyword %q at %q", segment, fmtPos(config, path, pos))
+	return nil, config, fmt.Errorf("unknown keyword %q at %q", segment, fmtPos(config, path, pos))
+}
+
+// resolveDynamicRef resolves the $dynamicRef of current. It is resolved like a
+// $ref, unless its fragment is the name of a $dynamicAnchor of the referenced
+// resource. Then, it resolves to the schema with that $dynamicAnchor in the
+// outermost resource of the dynamic scope having one, e.g. to extend a recursive
+// schema:
+//
+//	{"$id": "strict.json", "$dynamicAnchor": "node", "$ref": "tree.json", ...}
+//	{"$id": "tree.json", "$dynamicAnchor": "node", "items": {"$dynamicRef": "#node"}}
+//	// items resolves to strict.json if entered through it, otherwise to tree.json
+func resolveDynamicRef(config ResolveConfig, current *Schema) (*Schema, ResolveConfig, error) {
+	ref := current.DynamicRef
+	uri, err := url.Parse(ref)
+	if err != nil || uri.Fragment == "" || uri.Fragment[0] == '/' {
+		return resolveReference(config, ref, current)
+	}
+
+	// The referenced resource must have the $dynamicAnchor itself.
+	resource, rc := config.resource, config
+	base := *uri
+	base.Fragment, base.RawFragment = "", ""
+	if base.String() != "" {
+		if resource, rc, err = resolveReference(config, base.String(), current); err != nil {
+			return nil, rc, err
+		}
+	}
+	target := dynamicAnchor(resource, uri.Fragment)
+	if target == nil {
+		return resolveReference(config, ref, current)
+	}
+
+	for _, e := range config.dynamicScope {
+		if s := dynamicAnchor(e.resource, uri.Fragment); s != nil {
+			rc.resource, rc.resourceURI = e.resource, e.uri
+			return s, rc, nil
+		}
+	}
+	return target, rc, nil
+}
+
+// dynamicAnchor returns the schema of the resource with the $dynamicAnchor name,
+// embedded resources are not searched.
+func dynamicAnchor(resource *Schema, name string) *Schema {
+	var found *Schema
+	_ = Walk(resource, func(ptr string, s *Schema) error {
+		switch {
+		case ptr != "/" && s.ID != "":
+			return Skip
+		case s.DynamicAnchor == name:
+			found = s
+			return SkipAll
+		}
+		return nil
+	})
+	return found
 }
 
 func getUnescapedPath(ref string) []string {
@@ -259,10 +572,13 @@ func getUnescapedPath(ref string) []string {
 		return nil
 	}
 
+	// The keys of the schema maps are stored as they appear in the document, so
+	// every segment is decoded exactly once. ~1 is replaced first, otherwise ~01
+	// would be decoded to / instead of ~1 (RFC 6901, section 4).
 	path := strings.Split(ref, "/")
 	for i := range path {
-		path[i] = strings.ReplaceAll(path[i], "~0", "~")
 		path[i] = strings.ReplaceAll(path[i], "~1", "/")
+		path[i] = strings.ReplaceAll(path[i], "~0", "~")
 	}
 
 	return path
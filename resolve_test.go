@@ -1,7 +1,11 @@
 package jsonschema_test
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	. "jsonschema"
+	"net/url"
 	"reflect"
 	"testing"
 )
@@ -18,6 +22,12 @@ func TestResolveReference(t *testing.T) {
 			}
 		}
     },
+    "dependentSchemas": {
+        "foo": {
+            "required": ["bar"],
+            "properties": { "bar": { "$ref": "#/$defs/single" } }
+        }
+    },
     "$defs": {
         "single": {
             "$anchor": "item",
@@ -127,6 +137,32 @@ func TestResolveReference(t *testing.T) {
 				Type: TypeSet{TypeString},
 			},
 		},
+		{
+			name: "dependent schema",
+			args: args{ref: "#/dependentSchemas/foo", resource: root},
+			want: &Schema{
+				Required: []string{"bar"},
+				Properties: map[string]Schema{
+					"bar": {Ref: "#/$defs/single"},
+				},
+			},
+		},
+		{
+			name: "reference within dependent schema",
+			args: args{ref: "#/dependentSchemas/foo/properties/bar", resource: root},
+			want: &Schema{
+				Anchor: "item",
+				Type:   TypeSet{TypeObject},
+				AdditionalProperties: &Schema{
+					Ref: "other.json",
+				},
+			},
+		},
+		{
+			name:    "unknown dependent schema",
+			args:    args{ref: "#/dependentSchemas/bar", resource: root},
+			wantErr: `unknown key "bar" at "https://example.net/root.json#/dependentSchemas"`,
+		},
 		{
 			name:    "unknown keyword in the middle of the pointer",
 			args:    args{ref: "#/items/unknown/additionalProperties", resource: root},
@@ -182,7 +218,7 @@ func TestResolveReference(t *testing.T) {
 				resource: root,
 			},
 			want: &Schema{
-				Schema:      "https://json-schema.org/draft/2020-12/schema",
+				Schema:      string(Draft2020Schema),
 				ID:          "file:///testdata/miscellaneous-examples/arrays.schema.json",
 				Comment:     "https://json-schema.org/learn/miscellaneous-examples#arrays-of-things",
 				Description: "A representation of a person, company, organization, or place",
@@ -307,6 +343,47 @@ func TestResolveReference(t *testing.T) {
 	}
 }
 
+func TestResolveReference_PropertyNames(t *testing.T) {
+	const schema = `{
+    "properties": {
+        "a~1b": {"const": "literal"},
+        "a/b": {"const": "slash"},
+        "~": {"const": "tilde"},
+        "c%d": {"properties": {"e~f": {"const": "nested"}}},
+        "refs": {"$ref": "#/properties/a~01b"}
+    }
+}`
+
+	root := &Schema{}
+	if err := json.Unmarshal([]byte(schema), root); err != nil {
+		t.Logf("unexpected error: %s", err)
+		t.FailNow()
+	}
+
+	tests := map[string]any{
+		"#/properties/a~01b":                 "literal",
+		"#/properties/a~1b":                  "slash",
+		"#/properties/a%7E1b":                "slash",
+		"#/properties/~0":                    "tilde",
+		"#/properties/c%25d/properties/e~0f": "nested",
+		"#/properties/refs":                  "literal",
+	}
+
+	for ref, expected := range tests {
+		s, err := ResolveReference(ResolveConfig{}, ref, root)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", ref, err)
+		} else if s.Const != expected {
+			t.Errorf("%s:\nhave %v\nneed %v", ref, s.Const, expected)
+		}
+	}
+
+	_, err := ResolveReference(ResolveConfig{}, "#/properties/a~1b/properties/x", root)
+	if expected := `unknown key "x" at "<root>#/properties/a~1b/properties"`; err == nil || err.Error() != expected {
+		t.Errorf("\nhave %v\nneed %s", err, expected)
+	}
+}
+
 func TestResolveReference_Embedded(t *testing.T) {
 	const idsSchema = `{
   "$id": "https://example.com/schema.json",
@@ -414,3 +491,409 @@ func TestResolveReference_Embedded(t *testing.T) {
 		}
 	}
 }
+
+func TestResolveReference_DynamicRef(t *testing.T) {
+	const treeSchema = `{
+  "$id": "https://example.com/tree.json",
+  "$dynamicAnchor": "node",
+  "type": "object",
+  "properties": {
+    "data": true,
+    "children": {
+      "type": "array",
+      "items": {"$dynamicRef": "#node"}
+    },
+    "parent": {"$dynamicRef": "#/properties/data"},
+    "sibling": {"$dynamicRef": "#named"}
+  },
+  "$defs": {
+    "named": {"$anchor": "named", "title": "named"}
+  }
+}`
+
+	const strictSchema = `{
+  "$id": "https://example.com/strict-tree.json",
+  "$dynamicAnchor": "node",
+  "$ref": "tree.json",
+  "unevaluatedProperties": false,
+  "$defs": {
+    "tree": ` + treeSchema + `
+  }
+}`
+
+	tree, strict := &Schema{}, &Schema{}
+	_ = tree.UnmarshalJSON([]byte(treeSchema))
+	_ = strict.UnmarshalJSON([]byte(strictSchema))
+
+	tests := []struct {
+		root     *Schema
+		ref      string
+		expected string
+	}{
+		// The outermost resource of the dynamic scope with the anchor is used.
+		{root: strict, ref: "tree.json#/properties/children/items", expected: "https://example.com/strict-tree.json"},
+		{root: strict, ref: "#/$defs/tree/properties/children/items", expected: "https://example.com/strict-tree.json"},
+		{root: tree, ref: "#/properties/children/items", expected: "https://example.com/tree.json"},
+		// A pointer or a plain $anchor is resolved like $ref.
+		{root: strict, ref: "tree.json#/properties/parent", expected: "data"},
+		{root: strict, ref: "tree.json#/properties/sibling", expected: "named"},
+	}
+
+	for i, test := range tests {
+		s, err := ResolveReference(ResolveConfig{}, test.ref, test.root)
+		if err != nil {
+			t.Errorf("test #%d: unexpected error: %s", i, err)
+			continue
+		}
+
+		var have string
+		switch {
+		case s.ID != "":
+			have = s.ID
+		case s.Title != "":
+			have = s.Title
+		case s.IsTrue():
+			have = "data"
+		}
+		if have != test.expected {
+			t.Errorf("test #%d: %q resolved to %s, need %s", i, test.ref, s, test.expected)
+		}
+	}
+}
+
+func TestResolveReference_Self(t *testing.T) {
+	const schema = `{
+  "$id": "https://example.com/root.json",
+  "type": "object",
+  "properties": {
+    "parent": {"$ref": "#"},
+    "nested": {"items": {"$ref": "#/"}},
+    "absolute": {"$ref": "https://example.com/root.json"}
+  },
+  "$defs": {
+    "other": {
+      "$id": "other.json",
+      "type": "string",
+      "$defs": {
+        "self": {"$ref": "#"}
+      }
+    }
+  }
+}`
+
+	root := &Schema{}
+	_ = root.UnmarshalJSON([]byte(schema))
+	other := root.Defs["other"]
+
+	noID := &Schema{
+		Type: TypeSet{TypeObject},
+		Properties: map[string]Schema{
+			"parent": {Ref: "#"},
+		},
+	}
+
+	tests := []struct {
+		ref      string
+		resource *Schema
+		want     *Schema
+	}{
+		{ref: "#/properties/parent", resource: root, want: root},
+		{ref: "#/properties/nested/items", resource: root, want: root},
+		{ref: "#/properties/absolute", resource: root, want: root},
+		{ref: "#/$defs/other/$defs/self", resource: root, want: &other},
+		{ref: "https://example.com/other.json#/$defs/self", resource: root, want: &other},
+		{ref: "#/properties/parent", resource: noID, want: noID},
+	}
+
+	for i, test := range tests {
+		s, err := ResolveReference(ResolveConfig{}, test.ref, test.resource)
+		if err != nil {
+			t.Errorf("unexpected error %s, test case at %d (%s)", err, i, test.ref)
+		} else if !reflect.DeepEqual(s, test.want) {
+			t.Errorf("unexpected value at %d using $ref %q:\nneed: %s\nhave: %s", i,
+				test.ref, test.want, s)
+		}
+	}
+}
+
+func TestResolveAll(t *testing.T) {
+	const schema = `{
+  "$id": "https://example.com/root.json",
+  "properties": {
+    "name": {"$ref": "#/$defs/name"},
+    "alias": {"$ref": "#/$defs/alias", "description": "An alias"},
+    "node": {"$ref": "#/$defs/node"},
+    "vegetables": {"$ref": "file:///testdata/miscellaneous-examples/arrays.schema.json#/properties/vegetables"}
+  },
+  "$defs": {
+    "name": {"type": "string", "minLength": 1},
+    "alias": {"$ref": "#/$defs/name"},
+    "node": {
+      "type": "object",
+      "properties": {
+        "children": {"type": "array", "items": {"$ref": "#/$defs/node"}}
+      }
+    }
+  }
+}`
+
+	root := &Schema{}
+	_ = root.UnmarshalJSON([]byte(schema))
+	original := Copy(*root)
+
+	s, err := ResolveAll(context.Background(), root, NewEmbeddedLoader(testdataFS))
+	if err != nil {
+		t.Logf("unexpected error: %s", err)
+		t.FailNow()
+	}
+
+	if !reflect.DeepEqual(*root, original) {
+		t.Errorf("root schema was modified")
+	}
+
+	name := Schema{Type: TypeSet{TypeString}, MinLength: ptr(1)}
+	node := Schema{
+		Type: TypeSet{TypeObject},
+		Properties: map[string]Schema{
+			"children": {Type: TypeSet{TypeArray}, Items: &Schema{Ref: "#/$defs/node"}},
+		},
+	}
+
+	expected := &Schema{
+		ID: "https://example.com/root.json",
+		Properties: map[string]Schema{
+			"name":  name,
+			"alias": {Description: "An alias", AllOf: []Schema{name}},
+			"node":  node,
+			"vegetables": {
+				Type: TypeSet{TypeArray},
+				Items: &Schema{
+					Type:     TypeSet{TypeObject},
+					Required: []string{"veggieName", "veggieLike"},
+					Properties: map[string]Schema{
+						"veggieName": {Type: TypeSet{TypeString}, Description: "The name of the vegetable."},
+						"veggieLike": {Type: TypeSet{TypeBoolean}, Description: "Do I like this vegetable?"},
+					},
+				},
+			},
+		},
+		Defs: map[string]Schema{
+			"name":  name,
+			"alias": name,
+			"node":  node,
+		},
+	}
+
+	if !reflect.DeepEqual(s, expected) {
+		t.Errorf("\nhave %s\nneed %s", s, expected)
+	}
+
+	_, err = ResolveAll(context.Background(), &Schema{Items: &Schema{Ref: "#/$defs/unknown"}}, nil)
+	if err == nil || err.Error() != `failed to resolve {"$ref": "#/$defs/unknown"}: unknown key "unknown" at "<root>#/$defs"` {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestResolveAll_LoadedWithoutID(t *testing.T) {
+	docs := map[string]string{
+		"https://example.com/schemas/person.json":         `{"properties": {"address": {"$ref": "common/address.json"}}}`,
+		"https://example.com/schemas/common/address.json": `{"properties": {"street": {"$ref": "#/$defs/street"}}, "$defs": {"street": {"type": "string"}}}`,
+	}
+
+	var loaded []string
+	loader := LoaderFunc(func(_ context.Context, uri *url.URL) (*Schema, error) {
+		u := *uri
+		u.Fragment = ""
+		loaded = append(loaded, u.String())
+
+		doc, ok := docs[u.String()]
+		if !ok {
+			return nil, UnsupportedURI
+		}
+
+		s := &Schema{}
+		if err := json.Unmarshal([]byte(doc), s); err != nil {
+			return nil, err
+		}
+		*uri = url.URL{Fragment: uri.Fragment}
+		return s, nil
+	})
+
+	root := &Schema{Ref: "https://example.com/schemas/person.json#/properties/address"}
+	s, err := ResolveAll(context.Background(), root, loader)
+	if err != nil {
+		t.Logf("unexpected error: %s", err)
+		t.FailNow()
+	}
+
+	expected := &Schema{
+		Properties: map[string]Schema{"street": {Type: TypeSet{TypeString}}},
+		Defs:       map[string]Schema{"street": {Type: TypeSet{TypeString}}},
+	}
+	if !reflect.DeepEqual(s, expected) {
+		t.Errorf("\nhave %s\nneed %s", s, expected)
+	}
+
+	need := []string{"https://example.com/schemas/person.json", "https://example.com/schemas/common/address.json"}
+	if !reflect.DeepEqual(loaded, need) {
+		t.Errorf("\nhave %v\nneed %v", loaded, need)
+	}
+}
+
+func TestResolveReferenceBase(t *testing.T) {
+	docs := map[string]string{
+		"https://example.com/schemas/a.json": `{"$defs": {
+			"x": {"$ref": "b.json"},
+			"y": {"properties": {"p": {"$ref": "b.json"}}}
+		}}`,
+		"https://example.com/schemas/b.json": `{"const": "b"}`,
+	}
+
+	loader := LoaderFunc(func(_ context.Context, uri *url.URL) (*Schema, error) {
+		u := *uri
+		u.Fragment = ""
+
+		doc, ok := docs[u.String()]
+		if !ok {
+			return nil, UnsupportedURI
+		}
+
+		s := &Schema{}
+		if err := json.Unmarshal([]byte(doc), s); err != nil {
+			return nil, err
+		}
+		*uri = url.URL{Fragment: uri.Fragment}
+		return s, nil
+	})
+
+	root := &Schema{ID: "https://example.com/root.json"}
+	config := ResolveConfig{Loader: loader}
+
+	// The reference of x is followed relative to a.json.
+	s, base, err := ResolveReferenceBase(config, "schemas/a.json#/$defs/x", root)
+	if err != nil {
+		t.Logf("unexpected error: %s", err)
+		t.FailNow()
+	}
+	if s.Const != "b" || base.String() != "https://example.com/schemas/b.json" {
+		t.Errorf("unexpected result %s with base %s", s, base)
+	}
+
+	s, base, err = ResolveReferenceBase(config, "schemas/a.json#/$defs/y", root)
+	if err != nil {
+		t.Logf("unexpected error: %s", err)
+		t.FailNow()
+	}
+	if base.String() != "https://example.com/schemas/a.json" {
+		t.Errorf("\nhave %s\nneed %s", base, "https://example.com/schemas/a.json")
+	}
+
+	p := s.Properties["p"]
+	s, err = ResolveReference(ResolveConfig{Loader: loader, BaseURI: base}, p.Ref, &p)
+	if err != nil {
+		t.Logf("unexpected error: %s", err)
+		t.FailNow()
+	}
+	if s.Const != "b" {
+		t.Errorf("unexpected result %s", s)
+	}
+
+	// Without the base URI, b.json is resolved relative to nothing.
+	if _, err = ResolveReference(ResolveConfig{Loader: loader}, p.Ref, &p); err == nil {
+		t.Errorf("expected error without base URI")
+	}
+}
+
+// TestResolveReferenceBase_RelativeID ensures a relative $id of the resource is
+// resolved against the base URI instead of replacing it.
+func TestResolveReferenceBase_RelativeID(t *testing.T) {
+	loader := LoaderFunc(func(_ context.Context, uri *url.URL) (*Schema, error) {
+		if u := *uri; u.Fragment == "" && u.String() == "https://example.com/schemas/b.json" {
+			*uri = url.URL{}
+			return &Schema{Const: "b"}, nil
+		}
+		return nil, UnsupportedURI
+	})
+
+	root := &Schema{}
+	_ = json.Unmarshal([]byte(`{
+		"$id": "schemas/root.json",
+		"$defs": {
+			"x": {"$ref": "b.json"},
+			"c": {"$id": "c.json", "const": "c"}
+		}
+	}`), root)
+	base, _ := url.Parse("https://example.com/")
+
+	tests := []struct {
+		ref, base string
+		expected  any
+	}{
+		{ref: "#", base: "https://example.com/schemas/root.json"},
+		{ref: "#/$defs/x", base: "https://example.com/schemas/b.json", expected: "b"},
+		{ref: "#/$defs/c", base: "https://example.com/schemas/c.json", expected: "c"},
+		{ref: "c.json", base: "https://example.com/schemas/c.json", expected: "c"},
+		{ref: "https://example.com/schemas/c.json", base: "https://example.com/schemas/c.json", expected: "c"},
+	}
+
+	for i, test := range tests {
+		s, b, err := ResolveReferenceBase(ResolveConfig{Loader: loader, BaseURI: base}, test.ref, root)
+		if err != nil {
+			t.Errorf("test #%d: unexpected error: %s", i, err)
+			continue
+		}
+		if s.Const != test.expected || b.String() != test.base {
+			t.Errorf("test #%d: %q resolved to %s with base %s, need base %s", i, test.ref, s, b, test.base)
+		}
+	}
+}
+
+func TestResolveReference_Error(t *testing.T) {
+	root := &Schema{}
+	_ = json.Unmarshal([]byte(`{
+		"$id": "https://example.com/root.json",
+		"$defs": {
+			"a": {"$ref": "#/$defs/b"},
+			"b": {"$ref": "other.json#/$defs/missing"}
+		}
+	}`), root)
+
+	loader := LoaderFunc(func(_ context.Context, uri *url.URL) (*Schema, error) {
+		if uri.String() != "https://example.com/other.json#/$defs/missing" {
+			return nil, UnsupportedURI
+		}
+		*uri = url.URL{Fragment: uri.Fragment}
+		return &Schema{ID: "https://example.com/other.json"}, nil
+	})
+
+	_, err := ResolveReference(ResolveConfig{Loader: loader}, "#/$defs/a", root)
+
+	var re *ResolveError
+	if !errors.As(err, &re) {
+		t.Logf("expected ResolveError, have %v", err)
+		t.FailNow()
+	}
+
+	hops := []RefHop{
+		{Ref: "#/$defs/b", Location: "https://example.com/root.json#/$defs/a"},
+		{Ref: "other.json#/$defs/missing", Location: "https://example.com/root.json#/$defs/b"},
+	}
+	if !reflect.DeepEqual(re.Hops, hops) {
+		t.Errorf("\nhave %v\nneed %v", re.Hops, hops)
+	}
+	if re.URI != "https://example.com/other.json#/$defs/missing" {
+		t.Errorf("have URI %q", re.URI)
+	}
+
+	msg := `failed to resolve {"$ref": "#/$defs/b"} at "https://example.com/root.json#/$defs/a": ` +
+		`failed to resolve {"$ref": "other.json#/$defs/missing"} at "https://example.com/root.json#/$defs/b": ` +
+		`unknown key "missing" at "https://example.com/other.json#/$defs"`
+	if err.Error() != msg {
+		t.Errorf("\nhave %s\nneed %s", err, msg)
+	}
+
+	_, err = ResolveReference(ResolveConfig{}, "#/$defs/c", root)
+	if !errors.As(err, &re) || len(re.Hops) != 0 || re.URI != "https://example.com/root.json#/$defs/c" {
+		t.Errorf("expected ResolveError without hops, have %#v", err)
+	}
+}
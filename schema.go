@@ -3,6 +3,7 @@ package jsonschema
 import (
 	"bytes"
 	"encoding/json"
+	"slices"
 )
 
 type Type string
@@ -41,6 +42,9 @@ var (
 	False = Schema{Not: &Schema{}}
 )
 
+// Schema is a JSON Schema of draft 2020-12. The order of the fields is the order
+// of the keywords in the encoding of a schema, see MarshalJSON. Keep the order
+// in mind when adding a field.
 type Schema struct {
 	// https://json-schema.org/draft/2020-12/meta/core
 
@@ -137,6 +141,12 @@ type Schema struct {
 	ReadOnly    *bool  `json:"readOnly,omitempty"`
 	WriteOnly   *bool  `json:"writeOnly,omitempty"`
 	Examples    []any  `json:"examples,omitempty"`
+
+	// Draft07Tuple is set by UnmarshalJSON if items was an array of schemas, the
+	// tuple form of draft-07 and earlier, which is decoded as prefixItems and
+	// additionalItems as items. It is not a keyword, the schema is encoded as
+	// draft 2020-12 regardless.
+	Draft07Tuple bool `json:"-"`
 }
 
 func (s *Schema) String() string {
@@ -151,23 +161,68 @@ func (s *Schema) UnmarshalJSON(b []byte) error {
 		*s = Schema{Not: &Schema{}}
 	} else {
 		type rawSchema Schema
-		var out rawSchema
+		var out struct {
+			rawSchema
+
+			// Draft-07 and earlier allow items to be an array of schemas, which
+			// got replaced by prefixItems. additionalItems got replaced by items.
+			Items           json.RawMessage `json:"items,omitempty"`
+			AdditionalItems *Schema         `json:"additionalItems,omitempty"`
+
+			// A const of null cannot be told apart from a missing const, it is
+			// represented by an enum only containing null instead.
+			Const json.RawMessage `json:"const,omitempty"`
+		}
 		if err := json.Unmarshal(b, &out); err != nil {
 			return err
 		}
-		*s = Schema(out)
+
+		if c := bytes.TrimSpace(out.Const); bytes.Equal(c, []byte("null")) {
+			if out.Enum == nil {
+				out.Enum = []any{nil}
+			} else if out.Enum = slices.DeleteFunc(out.Enum, func(v any) bool { return v != nil }); len(out.Enum) == 0 {
+				// An empty enum is not encoded, the schema allowing no value at
+				// all is the false schema.
+				*s = False
+				return nil
+			}
+		} else if len(c) > 0 {
+			if err := json.Unmarshal(c, &out.rawSchema.Const); err != nil {
+				return err
+			}
+		}
+
+		if items := bytes.TrimSpace(out.Items); len(items) > 0 && items[0] == '[' {
+			if err := json.Unmarshal(items, &out.PrefixItems); err != nil {
+				return err
+			}
+			out.rawSchema.Items = out.AdditionalItems
+			out.Draft07Tuple = true
+		} else if len(items) > 0 {
+			if err := json.Unmarshal(items, &out.rawSchema.Items); err != nil {
+				return err
+			}
+		}
+		*s = Schema(out.rawSchema)
 	}
 	return nil
 }
 
-func (s *Schema) MarshalJSON() ([]byte, error) {
+// MarshalJSON encodes s as the boolean schema true or false if possible, or as
+// an object otherwise. The keywords are written in a fixed order, grouped by
+// vocabulary like the fields of Schema: the core keywords starting with $schema,
+// $id and $ref, the applicators, the validation keywords, the unevaluated
+// keywords, format, the content keywords and the annotations. The keys of
+// objects like properties and $defs are sorted, so that equal schemas have equal
+// encodings.
+func (s Schema) MarshalJSON() ([]byte, error) {
 	if s.IsFalse() {
 		return []byte("false"), nil
 	} else if s.IsTrue() {
 		return []byte("true"), nil
 	} else {
 		type rawSchema Schema
-		out := rawSchema(*s)
+		out := rawSchema(s)
 		return json.Marshal(out)
 	}
 }
@@ -263,3 +318,24 @@ func (s *Schema) IsTrue() bool {
 func (s *Schema) IsFalse() bool {
 	return s.Not != nil && s.Not.IsTrue()
 }
+
+// IsNullable returns true if the Schema explicitly allows null, either by its type
+// set or by a oneOf or anyOf branch only allowing null. Both encodings are used by
+// FromGoType.
+//
+//	{"type":["string","null"]}                      // true
+//	{"oneOf":[{"$ref":"#/$defs/A"},{"type":"null"}]} // true
+//	{}                                              // false
+func (s *Schema) IsNullable() bool {
+	if slices.Contains(s.Type, TypeNull) {
+		return true
+	}
+	for _, branches := range [][]Schema{s.OneOf, s.AnyOf} {
+		for i := range branches {
+			if slices.Equal(branches[i].Type, TypeSet{TypeNull}) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -14,6 +14,8 @@ func TestSchema_IsTrue(t *testing.T) {
 		{Ref: "https://example.com/true.schema.json"},
 		{Defs: map[string]Schema{"true": {}}},
 		{Description: "A schema that evaluates to true"},
+		{Anchor: "node"},
+		{DynamicAnchor: "node"},
 	}
 
 	for i, schema := range schemas {
@@ -41,6 +43,28 @@ func TestSchema_IsFalse(t *testing.T) {
 	}
 }
 
+func TestSchema_IsNullable(t *testing.T) {
+	tests := []struct {
+		schema   Schema
+		nullable bool
+	}{
+		{schema: Schema{Type: TypeSet{TypeString, TypeNull}}, nullable: true},
+		{schema: Schema{Type: TypeSet{TypeNull}}, nullable: true},
+		{schema: Schema{OneOf: []Schema{{Ref: "#/$defs/A"}, {Type: TypeSet{TypeNull}}}}, nullable: true},
+		{schema: Schema{AnyOf: []Schema{{Type: TypeSet{TypeNull}}, {Type: TypeSet{TypeString}}}}, nullable: true},
+		{schema: Schema{}},
+		{schema: Schema{Type: TypeSet{TypeString}}},
+		{schema: Schema{OneOf: []Schema{{Type: TypeSet{TypeNull, TypeString}}, {Type: TypeSet{TypeInteger}}}}},
+		{schema: Schema{AllOf: []Schema{{Type: TypeSet{TypeNull}}}}},
+	}
+
+	for i, test := range tests {
+		if test.schema.IsNullable() != test.nullable {
+			t.Errorf("schema at %d: have %t, need %t: %s", i, !test.nullable, test.nullable, test.schema.String())
+		}
+	}
+}
+
 func TestSchema_MarshalJSON(t *testing.T) {
 	tests := []struct {
 		schema Schema
@@ -54,6 +78,14 @@ func TestSchema_MarshalJSON(t *testing.T) {
 			schema: Schema{Ref: "https://example.com/test.schema.json"},
 			json:   `{"$ref":"https://example.com/test.schema.json"}`,
 		},
+		{
+			schema: Schema{Properties: map[string]Schema{"a": {}, "b": {Not: &Schema{}}}},
+			json:   `{"properties":{"a":true,"b":false}}`,
+		},
+		{
+			schema: Schema{AdditionalProperties: &Schema{}},
+			json:   `{"additionalProperties":true}`,
+		},
 	}
 
 	for i, test := range tests {
@@ -72,6 +104,148 @@ func TestSchema_MarshalJSON(t *testing.T) {
 	}
 }
 
+func TestSchema_MarshalJSON_Anchors(t *testing.T) {
+	in := `{"$dynamicAnchor":"tree","$defs":{"node":{"$anchor":"node","$dynamicAnchor":"tree"}}}`
+	expected := Schema{
+		DynamicAnchor: "tree",
+		Defs:          map[string]Schema{"node": {Anchor: "node", DynamicAnchor: "tree"}},
+	}
+
+	var s Schema
+	if err := json.Unmarshal([]byte(in), &s); err != nil {
+		t.Logf("unexpected error: %s", err)
+		t.FailNow()
+	}
+	if !reflect.DeepEqual(s, expected) {
+		t.Errorf("\nhave %s\nneed %s", &s, &expected)
+	}
+
+	if b, err := json.Marshal(s); err != nil || string(b) != in {
+		t.Errorf("\nhave %s\nneed %s", b, in)
+	}
+
+	if c := Copy(s); !reflect.DeepEqual(c, s) {
+		t.Errorf("copy lost the anchors: %s", &c)
+	}
+}
+
+func TestSchema_MarshalJSON_Content(t *testing.T) {
+	in := `{"type":["string"],"format":"json","contentEncoding":"base64","contentMediaType":"application/json","contentSchema":{"required":["a"],"type":["object"]}}`
+	expected := Schema{
+		Type:             TypeSet{TypeString},
+		Format:           ptr("json"),
+		ContentEncoding:  ptr("base64"),
+		ContentMediaType: ptr("application/json"),
+		ContentSchema:    &Schema{Type: TypeSet{TypeObject}, Required: []string{"a"}},
+	}
+
+	var s Schema
+	if err := json.Unmarshal([]byte(in), &s); err != nil {
+		t.Logf("unexpected error: %s", err)
+		t.FailNow()
+	}
+	if !reflect.DeepEqual(s, expected) {
+		t.Errorf("\nhave %s\nneed %s", &s, &expected)
+	}
+
+	b, err := json.Marshal(s)
+	if err != nil {
+		t.Logf("unexpected error: %s", err)
+		t.FailNow()
+	}
+	var again Schema
+	if err = json.Unmarshal(b, &again); err != nil || !reflect.DeepEqual(again, expected) {
+		t.Errorf("round trip changed the schema to %s", b)
+	}
+
+	if c := Copy(s); !reflect.DeepEqual(c, s) || c.ContentSchema == s.ContentSchema {
+		t.Errorf("expected independent copy, have %s", &c)
+	}
+
+	var ptrs []string
+	_ = Walk(&s, func(ptr string, _ *Schema) error {
+		ptrs = append(ptrs, ptr)
+		return nil
+	})
+	if !reflect.DeepEqual(ptrs, []string{"/", "/contentSchema"}) {
+		t.Errorf("have walked %q", ptrs)
+	}
+}
+
+// TestSchema_MarshalJSON_Order ensures the keywords of a schema are encoded in a
+// fixed order, regardless of the order they were decoded in.
+func TestSchema_MarshalJSON_Order(t *testing.T) {
+	in := `{"title":"Pet","required":["name"],"properties":{"name":{"type":"string","minLength":1},"age":{"minimum":0,"type":"integer"},"color":{"enum":["black","white"]}},"type":"object","$defs":{"b":{},"a":false},"$ref":"#/$defs/b","$id":"https://example.com/pet","$schema":"https://json-schema.org/draft/2020-12/schema","additionalProperties":false,"allOf":[{"description":"x","maxProperties":3}]}`
+	out := `{"$schema":"https://json-schema.org/draft/2020-12/schema","$id":"https://example.com/pet","$ref":"#/$defs/b","$defs":{"a":false,"b":true},"allOf":[{"maxProperties":3,"description":"x"}],"properties":{"age":{"type":["integer"],"minimum":0},"color":{"enum":["black","white"]},"name":{"type":["string"],"minLength":1}},"additionalProperties":false,"type":["object"],"required":["name"],"title":"Pet"}`
+
+	var s Schema
+	if err := json.Unmarshal([]byte(in), &s); err != nil {
+		t.Logf("unexpected error: %s", err)
+		t.FailNow()
+	}
+
+	for i := 0; i < 10; i++ {
+		b, err := json.Marshal(s)
+		if err != nil {
+			t.Logf("unexpected error: %s", err)
+			t.FailNow()
+		}
+		if string(b) != out {
+			t.Logf("\nhave %s\nneed %s", b, out)
+			t.FailNow()
+		}
+	}
+}
+
+// TestSchema_MarshalJSON_NullAndFalse ensures a schema only allowing null is not
+// mistaken for the false schema, which allows nothing, or for the true schema.
+func TestSchema_MarshalJSON_NullAndFalse(t *testing.T) {
+	tests := []struct {
+		in, out string
+		schema  Schema
+		null    bool
+	}{
+		{in: `{"type":"null"}`, out: `{"type":["null"]}`, schema: Schema{Type: TypeSet{TypeNull}}, null: true},
+		{in: `{"type":["null"]}`, out: `{"type":["null"]}`, schema: Schema{Type: TypeSet{TypeNull}}, null: true},
+		{in: `{"const":null}`, out: `{"enum":[null]}`, schema: Schema{Enum: []any{nil}}, null: true},
+		{in: `{"const":null,"enum":[1,null]}`, out: `{"enum":[null]}`, schema: Schema{Enum: []any{nil}}, null: true},
+		{in: `{"const":null,"enum":[null]}`, out: `{"enum":[null]}`, schema: Schema{Enum: []any{nil}}, null: true},
+		{in: `{"const":null,"enum":[1]}`, out: `false`, schema: False},
+		{in: `{"type":"null","const":null,"enum":[1]}`, out: `false`, schema: False},
+		{in: `false`, out: `false`, schema: False},
+		{in: `{"not":true}`, out: `false`, schema: False},
+		{in: `{"not":{"type":"null"}}`, out: `{"not":{"type":["null"]}}`, schema: Schema{Not: &Schema{Type: TypeSet{TypeNull}}}},
+		{in: `true`, out: `true`, schema: True, null: true},
+	}
+
+	for i, test := range tests {
+		var s Schema
+		if err := json.Unmarshal([]byte(test.in), &s); err != nil {
+			t.Errorf("test #%d: unexpected error: %s", i, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(s, test.schema) {
+			t.Errorf("test #%d: have %s, need %s", i, &s, &test.schema)
+		}
+
+		b, err := json.Marshal(s)
+		if err != nil || string(b) != test.out {
+			t.Errorf("test #%d: have %s, need %s", i, b, test.out)
+		}
+
+		if err = s.Validate(nil); (err == nil) != test.null {
+			t.Errorf("test #%d: have valid=%t for null, need valid=%t", i, err == nil, test.null)
+		}
+
+		// The encoding must be stable once marshaled.
+		var again Schema
+		if err = json.Unmarshal(b, &again); err != nil || !reflect.DeepEqual(again, s) {
+			t.Errorf("test #%d: round trip changed the schema to %s", i, &again)
+		}
+	}
+}
+
 func TestTypeSet_UnmarshalJSON(t *testing.T) {
 	tests := []struct {
 		json   string
@@ -86,6 +260,25 @@ func TestTypeSet_UnmarshalJSON(t *testing.T) {
 			json:   `{"$ref":"https://example.com/test.schema.json"}`,
 			schema: Schema{Ref: "https://example.com/test.schema.json"},
 		},
+		{json: `{"items":{"type":"string"}}`, schema: Schema{Items: &Schema{Type: []Type{TypeString}}}},
+		{json: `{"items":null}`, schema: Schema{}},
+		// Draft-07 tuple validation
+		{
+			json: `{"items":[{"type":"string"},{"type":"number"}]}`,
+			schema: Schema{
+				PrefixItems: []Schema{
+					{Type: []Type{TypeString}},
+					{Type: []Type{TypeNumber}},
+				},
+				Draft07Tuple: true,
+			},
+		},
+		{
+			json:   `{"items":[true],"additionalItems":false}`,
+			schema: Schema{PrefixItems: []Schema{{}}, Items: &Schema{Not: &Schema{}}, Draft07Tuple: true},
+		},
+		// additionalItems is ignored unless items is an array
+		{json: `{"items":true,"additionalItems":false}`, schema: Schema{Items: &Schema{}}},
 		// Numbers are converted to float64 before being written to an any field
 		{json: `{"const":123,"not":{}}`, schema: Schema{Const: float64(123), Not: &Schema{}}},
 	}
@@ -0,0 +1,124 @@
+package jsonschema
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// SchemaSet is a collection of schema documents that may reference each other.
+// Every document and every schema resource embedded in it is identified by its
+// absolute base URI. A SchemaSet implements Loader, so it can be used to resolve
+// references to its documents from other schemas as well.
+//
+// The zero value is an empty set ready to use. Added schemas must not be modified.
+type SchemaSet struct {
+	resources map[string]*Schema
+}
+
+// Add adds the schema document s retrievable from the absolute uri. The embedded
+// schema resources of s are added by their base URI. If s has an $id, it is
+// resolved against uri and the document is added by both URIs.
+func (set *SchemaSet) Add(uri string, s *Schema) error {
+	base, err := url.Parse(uri)
+	if err != nil {
+		return fmt.Errorf("invalid schema URI: %w", err)
+	}
+	if !base.IsAbs() || base.Fragment != "" {
+		return fmt.Errorf("schema URI %q must be absolute without a fragment", uri)
+	}
+
+	if set.resources == nil {
+		set.resources = make(map[string]*Schema)
+	}
+
+	// The document is copied with an absolute $id, so that the references in it
+	// are resolved against the URI it was added by.
+	doc := *s
+	if s.ID != "" {
+		id, err := url.Parse(s.ID)
+		if err != nil {
+			return fmt.Errorf("invalid $id: %w", err)
+		}
+		base = base.ResolveReference(id)
+	}
+	doc.ID = base.String()
+
+	set.resources[uri] = &doc
+	set.resources[doc.ID] = &doc
+
+	ids, _ := ComputeIdentifiers(doc)
+	for ptr, identifiers := range ids {
+		if identifiers.BaseURI+"#" != identifiers.CanonResourcePointerURI {
+			continue
+		}
+
+		r, _, err := resolveRef(ResolveConfig{ignoreRefs: true}, &doc, getUnescapedPath(ptr), 0)
+		if err != nil {
+			return fmt.Errorf("failed to locate embedded resource %q: %w", identifiers.BaseURI, err)
+		}
+
+		resource := *r
+		resource.ID = identifiers.BaseURI
+		set.resources[identifiers.BaseURI] = &resource
+	}
+	return nil
+}
+
+// Load returns the schema resource identified by uri without its fragment. The
+// uri is replaced with a reference containing only the fragment, relative to the
+// returned schema. UnsupportedURI is returned if the set does not contain the
+// resource.
+func (set *SchemaSet) Load(_ context.Context, uri *url.URL) (*Schema, error) {
+	u := *uri
+	u.Fragment = ""
+
+	s, ok := set.resources[u.String()]
+	if !ok {
+		return nil, UnsupportedURI
+	}
+
+	*uri = url.URL{Fragment: uri.Fragment}
+	return s, nil
+}
+
+// Resolve resolves the absolute reference ref against the schemas of the set.
+func (set *SchemaSet) Resolve(ref string) (*Schema, error) {
+	uri, err := url.Parse(ref)
+	if err != nil {
+		return nil, fmt.Errorf("invalid reference: %w", err)
+	}
+	if !uri.IsAbs() {
+		return nil, fmt.Errorf("reference %q is not absolute", ref)
+	}
+
+	config := ResolveConfig{Context: context.Background(), Loader: set}
+	doc, err := set.Load(config.Context, uri)
+	if err != nil {
+		return nil, fmt.Errorf("unable to locate resource %q: %w", ref, err)
+	}
+	return ResolveReference(config, uri.String(), doc)
+}
+
+// Validate validates data against the schema referenced by the absolute uri, see
+// Schema.Validate. References are followed within the set, the keyword locations
+// of the failed assertions are relative to the referenced schema.
+func (set *SchemaSet) Validate(uri string, data any) error {
+	if _, err := set.Resolve(uri); err != nil {
+		return err
+	}
+
+	c, err := Compile(context.Background(), &Schema{Ref: uri}, set)
+	if err != nil {
+		return err
+	}
+	r := c.Validate(data)
+	if r.Valid {
+		return nil
+	}
+	for _, e := range r.Errors {
+		e.KeywordLocation = strings.TrimPrefix(e.KeywordLocation, "/$ref")
+	}
+	return r.Errors
+}
@@ -0,0 +1,125 @@
+package jsonschema_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	. "jsonschema"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestSchemaSet(t *testing.T) {
+	const person = `{
+    "type": "object",
+    "properties": {
+        "name": { "$ref": "common.json#/$defs/name" },
+        "address": { "$ref": "https://example.com/address.json" }
+    },
+    "$defs": {
+        "address": {
+            "$id": "address.json",
+            "dependentRequired": { "street": ["city"] },
+            "$defs": {
+                "city": { "$anchor": "city", "type": "string" }
+            }
+        }
+    }
+}`
+	const common = `{
+    "$id": "https://example.com/common.json",
+    "$defs": {
+        "name": { "type": "string", "minLength": 1 }
+    }
+}`
+
+	var set SchemaSet
+	for uri, doc := range map[string]string{
+		"https://example.com/person.json": person,
+		"https://example.com/other.json":  common,
+	} {
+		s := &Schema{}
+		if err := json.Unmarshal([]byte(doc), s); err != nil {
+			t.Fatalf("invalid schema %s: %s", uri, err)
+		}
+		if err := set.Add(uri, s); err != nil {
+			t.Fatalf("failed to add %s: %s", uri, err)
+		}
+	}
+
+	tests := []struct {
+		ref, err string
+		types    TypeSet
+	}{
+		{ref: "https://example.com/person.json", types: TypeSet{TypeObject}},
+		{ref: "https://example.com/person.json#/properties/name", types: TypeSet{TypeString}},
+		{ref: "https://example.com/other.json#/$defs/name", types: TypeSet{TypeString}},
+		{ref: "https://example.com/common.json#/$defs/name", types: TypeSet{TypeString}},
+		{ref: "https://example.com/address.json#city", types: TypeSet{TypeString}},
+		{ref: "https://example.com/address.json#/$defs/city", types: TypeSet{TypeString}},
+		{ref: "common.json", err: `reference "common.json" is not absolute`},
+		{ref: "https://example.com/unknown.json", err: `unable to locate resource "https://example.com/unknown.json": unsupported URI`},
+	}
+
+	for _, test := range tests {
+		s, err := set.Resolve(test.ref)
+		if test.err != "" {
+			if err == nil || err.Error() != test.err {
+				t.Errorf("%s: expected error %q, got %v", test.ref, test.err, err)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", test.ref, err)
+		} else if !reflect.DeepEqual(s.Type, test.types) {
+			t.Errorf("%s: have type %v, need %v", test.ref, s.Type, test.types)
+		}
+	}
+
+	t.Run("loader", func(t *testing.T) {
+		root := &Schema{Ref: "https://example.com/common.json#/$defs/name"}
+		s, err := ResolveReference(ResolveConfig{Loader: &set}, root.Ref, root)
+		if err != nil {
+			t.Logf("unexpected error: %s", err)
+			t.FailNow()
+		}
+		if s.MinLength == nil || *s.MinLength != 1 {
+			t.Errorf("resolved the wrong schema: %s", s)
+		}
+
+		uri, _ := url.Parse("https://example.com/unknown.json")
+		if _, err = set.Load(context.Background(), uri); !errors.Is(err, UnsupportedURI) {
+			t.Errorf("expected UnsupportedURI, got %v", err)
+		}
+	})
+
+	t.Run("validate", func(t *testing.T) {
+		if err := set.Validate("https://example.com/address.json", map[string]any{"city": "x"}); err != nil {
+			t.Errorf("unexpected error: %s", err)
+		}
+
+		err := set.Validate("https://example.com/address.json", map[string]any{"street": "x"})
+		var errs ValidationErrors
+		if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Keyword != "dependentRequired" {
+			t.Errorf("expected a dependentRequired error, got %v", err)
+		}
+
+		// The name is constrained by the other document.
+		err = set.Validate("https://example.com/person.json", map[string]any{"name": ""})
+		if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Keyword != "minLength" ||
+			errs[0].KeywordLocation != "/properties/name/$ref/minLength" {
+			t.Errorf("expected a minLength error, got %v", err)
+		}
+		if err = set.Validate("https://example.com/person.json", map[string]any{"name": "a"}); err != nil {
+			t.Errorf("unexpected error: %s", err)
+		}
+	})
+
+	t.Run("invalid uri", func(t *testing.T) {
+		if err := set.Add("relative.json", &Schema{}); err == nil {
+			t.Errorf("expected an error for a relative URI")
+		}
+	})
+}
@@ -0,0 +1,251 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// tagOption is a single key=value pair of a jsonschema struct tag. The value
+// is empty for options without a value.
+type tagOption struct {
+	key, value string
+}
+
+// parseTagOptions parses a comma separated list of options. A comma that is
+// part of a value must be escaped using a backslash:
+//
+//	`jsonschema:"keyPattern=^[a-z]{1\\,8}$,keyMinLength=1"`
+func parseTagOptions(tag string) []tagOption {
+	if tag == "" {
+		return nil
+	}
+
+	var (
+		opts []tagOption
+		sb   strings.Builder
+	)
+	for i := 0; i <= len(tag); i++ {
+		if i < len(tag) && tag[i] == '\\' && i+1 < len(tag) && tag[i+1] == ',' {
+			sb.WriteByte(',')
+			i++
+			continue
+		}
+		if i < len(tag) && tag[i] != ',' {
+			sb.WriteByte(tag[i])
+			continue
+		}
+
+		key, value, _ := strings.Cut(sb.String(), "=")
+		opts = append(opts, tagOption{key: key, value: value})
+		sb.Reset()
+	}
+	return opts
+}
+
+// hasOption returns whether opts contains an option with the given key.
+func hasOption(opts []tagOption, key string) bool {
+	return slices.ContainsFunc(opts, func(opt tagOption) bool {
+		return opt.key == key
+	})
+}
+
+// applyTagOptions applies the jsonschema struct tag options of f to the
+// schema generated for the field. If the schema references a named type, the
+// comment and description options are also applied to its definition, unless
+// already set. The field keeps its own metadata next to the reference.
+func applyTagOptions(f field, s *Schema, opts *goTypeOptions) error {
+	t := f.typ
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	def := opts.definition(s)
+	if def == nil {
+		def = &Schema{}
+	}
+
+	for _, opt := range f.options {
+		switch opt.key {
+		case "keyPattern", "keyMinLength", "keyMaxLength":
+			if t.Kind() != reflect.Map || t.Key().Kind() != reflect.String {
+				return fmt.Errorf("option %q requires a string keyed map", opt.key)
+			}
+
+			if s.PropertyNames == nil {
+				s.PropertyNames = &Schema{}
+			}
+
+			var err error
+			switch opt.key {
+			case "keyPattern":
+				if _, err = regexp.Compile(opt.value); err == nil {
+					s.PropertyNames.Pattern = ptr(opt.value)
+				}
+			case "keyMinLength":
+				s.PropertyNames.MinLength, err = parseLength(opt.value)
+			case "keyMaxLength":
+				s.PropertyNames.MaxLength, err = parseLength(opt.value)
+			}
+			if err != nil {
+				return fmt.Errorf("invalid option %q: %w", opt.key, err)
+			}
+		case "minLength", "maxLength", "pattern":
+			// A quoted field is encoded as a string, its pattern is replaced.
+			if (t.Kind() != reflect.String || t == numberType) && !f.quoted {
+				return fmt.Errorf("option %q requires a string type", opt.key)
+			}
+
+			var err error
+			switch opt.key {
+			case "minLength":
+				s.MinLength, err = parseLength(opt.value)
+			case "maxLength":
+				s.MaxLength, err = parseLength(opt.value)
+			case "pattern":
+				if _, err = regexp.Compile(opt.value); err == nil {
+					s.Pattern = ptr(opt.value)
+				}
+			}
+			if err != nil {
+				return fmt.Errorf("invalid option %q: %w", opt.key, err)
+			}
+		case "minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum":
+			// A quoted field is encoded as a string, a numeric bound has no effect.
+			if !isNumeric(t) || f.quoted {
+				return fmt.Errorf("option %q requires a numeric type", opt.key)
+			}
+
+			n, err := parseNumber(opt.value)
+			if err != nil {
+				return fmt.Errorf("invalid option %q: %w", opt.key, err)
+			}
+			switch opt.key {
+			case "minimum":
+				s.Minimum = n
+			case "maximum":
+				s.Maximum = n
+			case "exclusiveMinimum":
+				s.ExclusiveMinimum = n
+			case "exclusiveMaximum":
+				s.ExclusiveMaximum = n
+			}
+		case "comment":
+			s.Comment = opt.value
+			if def.Comment == "" {
+				def.Comment = opt.value
+			}
+		case "title":
+			s.Title = opt.value
+		case "description":
+			s.Description = opt.value
+			if def.Description == "" {
+				def.Description = opt.value
+			}
+		case "enum":
+			v, err := parseEnumValue(t, f.quoted, opt.value)
+			if err != nil {
+				return fmt.Errorf("invalid option %q: %w", opt.key, err)
+			}
+			s.Enum = append(s.Enum, v)
+		case "examples":
+			examples, err := parseExamples(opt.value)
+			if err != nil {
+				return fmt.Errorf("invalid option %q: %w", opt.key, err)
+			}
+			s.Examples = append(s.Examples, examples...)
+		case "inline":
+			// The option is applied when the schema of the field is generated.
+			if t.Name() == "" {
+				return fmt.Errorf("option %q requires a named type", opt.key)
+			}
+		default:
+			return fmt.Errorf("unknown option %q", opt.key)
+		}
+	}
+
+	// A nil pointer is encoded as null, unless it is omitted.
+	if s.Enum != nil && f.typ.Kind() == reflect.Ptr && !f.omitEmpty {
+		s.Enum = append(s.Enum, nil)
+	}
+	return nil
+}
+
+// parseEnumValue parses v as a value of the type t, the value of a quoted field is
+// a string.
+func parseEnumValue(t reflect.Type, quoted bool, v string) (any, error) {
+	if quoted {
+		return v, nil
+	}
+	if t == numberType {
+		n, err := parseNumber(v)
+		if err != nil {
+			return nil, err
+		}
+		return *n, nil
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return v, nil
+	case reflect.Bool:
+		return strconv.ParseBool(v)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(v, 10, t.Bits())
+		return int(n), err
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(v, 10, t.Bits())
+		return uint(n), err
+	case reflect.Float32, reflect.Float64:
+		return strconv.ParseFloat(v, t.Bits())
+	default:
+		return nil, fmt.Errorf("unsupported type %s", t)
+	}
+}
+
+func parseLength(v string) (*int, error) {
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return nil, err
+	} else if n < 0 {
+		return nil, fmt.Errorf("negative length %d", n)
+	}
+	return &n, nil
+}
+
+// parseExamples parses a list of JSON values separated by commas or semicolons.
+// Commas must be escaped like in any other value, e.g. `examples=1\,2;"a"` is
+// parsed as [1, 2, "a"]. A semicolon always separates values, even in a string.
+func parseExamples(v string) ([]any, error) {
+	var examples []any
+	for _, part := range strings.Split(v, ";") {
+		var values []any
+		if err := json.Unmarshal([]byte("["+part+"]"), &values); err != nil {
+			return nil, err
+		}
+		examples = append(examples, values...)
+	}
+	return examples, nil
+}
+
+// isNumeric returns whether t is encoded as a JSON number.
+func isNumeric(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8,
+		reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+		return true
+	}
+	return t == numberType
+}
+
+// parseNumber parses v as a JSON number literal.
+func parseNumber(v string) (*json.Number, error) {
+	if v == "" || strings.TrimSpace(v) != v || (v[0] != '-' && (v[0] < '0' || v[0] > '9')) || !json.Valid([]byte(v)) {
+		return nil, fmt.Errorf("invalid number %q", v)
+	}
+	return ptr(json.Number(v)), nil
+}
@@ -0,0 +1,384 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// GenerateTypeScript writes TypeScript declarations for schema to w. Every entry
+// of $defs is declared as an exported type of the same name and the root schema
+// is declared as Schema. Objects with properties are declared as interfaces, all
+// other schemas as type aliases. Descriptions are added as doc comments.
+//
+//	{"type":["string","null"]}                  // string | null
+//	{"type":"array","items":{"$ref":"#/$defs/A"}} // A[]
+//	{"enum":["a","b"]}                          // "a" | "b"
+//
+// The names of $defs that are not valid TypeScript identifiers have the invalid
+// characters replaced by an underscore, reserved words are prefixed by one. A
+// name that is already declared, including Schema, is suffixed by a number:
+//
+//	$defs a-b and a_b   // a_b and a_b_2
+//	$defs class         // _class
+//
+// Only references to the root schema and its $defs are supported. Keywords without
+// a TypeScript equivalent, e.g. minimum or pattern, are ignored. Declared
+// properties of an object are not combined with an index signature, the
+// additionalProperties of an object with properties are ignored.
+func GenerateTypeScript(schema *Schema, w io.Writer) error {
+	g := tsGenerator{defs: schema.Defs, names: tsNames(schema.Defs)}
+
+	for _, name := range sortedKeys(schema.Defs) {
+		def := schema.Defs[name]
+		if err := g.declare(g.names[name], &def); err != nil {
+			return fmt.Errorf("schema.GenerateTypeScript: $defs/%s: %w", name, err)
+		}
+	}
+
+	root := *schema
+	root.Defs = nil
+	if err := g.declare("Schema", &root); err != nil {
+		return fmt.Errorf("schema.GenerateTypeScript: %w", err)
+	}
+
+	_, err := io.WriteString(w, g.sb.String())
+	return err
+}
+
+type tsGenerator struct {
+	defs  map[string]Schema
+	names map[string]string // declared names of defs
+	sb    strings.Builder
+}
+
+// tsNames returns the declared names of defs, see GenerateTypeScript. The root
+// schema is declared as Schema.
+func tsNames(defs map[string]Schema) map[string]string {
+	names := make(map[string]string, len(defs))
+	declared := map[string]bool{"Schema": true}
+	for _, name := range sortedKeys(defs) {
+		id := tsIdentifier(name)
+		if tsReserved[id] {
+			id = "_" + id
+		}
+		for i, base := 2, id; declared[id]; i++ {
+			id = base + "_" + strconv.Itoa(i)
+		}
+		names[name] = id
+		declared[id] = true
+	}
+	return names
+}
+
+// declare writes the declaration of a type with the given name.
+func (g *tsGenerator) declare(name string, s *Schema) error {
+	if g.sb.Len() > 0 {
+		g.sb.WriteString("\n")
+	}
+	tsDocComment(&g.sb, s, "")
+
+	if isInterface(s) {
+		body, err := g.objectBody(s, "")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&g.sb, "export interface %s %s\n", name, body)
+		return nil
+	}
+
+	expr, err := g.expr(s, "")
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(&g.sb, "export type %s = %s;\n", name, expr)
+	return nil
+}
+
+// tsDocComment writes the description of s as doc comment.
+func tsDocComment(sb *strings.Builder, s *Schema, indent string) {
+	if s.Description == "" {
+		return
+	}
+
+	sb.WriteString(indent + "/**\n")
+	for _, line := range strings.Split(strings.ReplaceAll(s.Description, "*/", `*\/`), "\n") {
+		sb.WriteString(strings.TrimRight(indent+" * "+line, " ") + "\n")
+	}
+	sb.WriteString(indent + " */\n")
+}
+
+// isInterface returns whether s is a plain object schema with properties.
+func isInterface(s *Schema) bool {
+	return len(s.Properties) > 0 && s.Ref == "" && s.Const == nil && s.Enum == nil &&
+		len(s.AllOf) == 0 && len(s.AnyOf) == 0 && len(s.OneOf) == 0 &&
+		(len(s.Type) == 0 || (len(s.Type) == 1 && s.Type[0] == TypeObject))
+}
+
+// expr returns the type expression of s. The ref, type, oneOf, anyOf and allOf
+// keywords are combined by an intersection.
+func (g *tsGenerator) expr(s *Schema, indent string) (string, error) {
+	if s.IsFalse() {
+		return "never", nil
+	}
+
+	var parts []string
+	if s.Ref != "" {
+		name, err := g.refName(s.Ref)
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, name)
+	}
+
+	t, err := g.valueType(s, indent)
+	if err != nil {
+		return "", err
+	}
+	if t != "" {
+		parts = append(parts, t)
+	}
+
+	for _, branches := range [][]Schema{s.OneOf, s.AnyOf} {
+		if len(branches) == 0 {
+			continue
+		}
+		union, err := g.exprs(branches, indent)
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, strings.Join(union, " | "))
+	}
+
+	all, err := g.exprs(s.AllOf, indent)
+	if err != nil {
+		return "", err
+	}
+	parts = append(parts, all...)
+
+	switch len(parts) {
+	case 0:
+		return "unknown", nil
+	case 1:
+		return parts[0], nil
+	}
+	for i := range parts {
+		parts[i] = tsParen(parts[i])
+	}
+	return strings.Join(parts, " & "), nil
+}
+
+func (g *tsGenerator) exprs(schemas []Schema, indent string) ([]string, error) {
+	exprs := make([]string, len(schemas))
+	for i := range schemas {
+		var err error
+		if exprs[i], err = g.expr(&schemas[i], indent); err != nil {
+			return nil, err
+		}
+	}
+	return exprs, nil
+}
+
+func (g *tsGenerator) refName(ref string) (string, error) {
+	if ref == "#" {
+		return "Schema", nil
+	}
+
+	path := getUnescapedPath(strings.TrimPrefix(ref, "#"))
+	if !strings.HasPrefix(ref, "#/") || len(path) != 2 || path[0] != "$defs" {
+		return "", fmt.Errorf("unsupported reference %q", ref)
+	}
+	if _, ok := g.defs[path[1]]; !ok {
+		return "", fmt.Errorf("unknown definition %q", ref)
+	}
+	return g.names[path[1]], nil
+}
+
+// valueType returns the type expression of the const, enum and type keywords of s,
+// or an empty string if s has none of them.
+func (g *tsGenerator) valueType(s *Schema, indent string) (string, error) {
+	if s.Const != nil {
+		return tsLiteral(s.Const)
+	}
+
+	if s.Enum != nil {
+		literals := make([]string, len(s.Enum))
+		for i, v := range s.Enum {
+			var err error
+			if literals[i], err = tsLiteral(v); err != nil {
+				return "", err
+			}
+		}
+		return strings.Join(literals, " | "), nil
+	}
+
+	types := s.Type
+	if len(types) == 0 {
+		switch {
+		case s.Properties != nil || s.AdditionalProperties != nil:
+			types = TypeSet{TypeObject}
+		case s.Items != nil || s.PrefixItems != nil:
+			types = TypeSet{TypeArray}
+		}
+	}
+
+	var union []string
+	for _, t := range types {
+		var expr string
+		switch t {
+		case TypeNull, TypeBoolean, TypeString:
+			expr = string(t)
+		case TypeNumber, TypeInteger:
+			expr = "number"
+		case TypeArray:
+			var err error
+			if expr, err = g.arrayType(s, indent); err != nil {
+				return "", err
+			}
+		case TypeObject:
+			var err error
+			if expr, err = g.objectType(s, indent); err != nil {
+				return "", err
+			}
+		default:
+			return "", fmt.Errorf("unknown type %q", t)
+		}
+
+		if !slices.Contains(union, expr) {
+			union = append(union, expr)
+		}
+	}
+	return strings.Join(union, " | "), nil
+}
+
+func (g *tsGenerator) arrayType(s *Schema, indent string) (string, error) {
+	var items string
+	if s.Items == nil {
+		items = "unknown"
+	} else if !s.Items.IsFalse() {
+		expr, err := g.expr(s.Items, indent)
+		if err != nil {
+			return "", err
+		}
+		items = expr
+	}
+
+	if len(s.PrefixItems) == 0 {
+		if items == "" {
+			return "[]", nil
+		}
+		return tsParen(items) + "[]", nil
+	}
+
+	elems, err := g.exprs(s.PrefixItems, indent)
+	if err != nil {
+		return "", err
+	}
+	if items != "" {
+		elems = append(elems, "..."+tsParen(items)+"[]")
+	}
+	return "[" + strings.Join(elems, ", ") + "]", nil
+}
+
+func (g *tsGenerator) objectType(s *Schema, indent string) (string, error) {
+	if len(s.Properties) > 0 {
+		return g.objectBody(s, indent)
+	}
+
+	values := "unknown"
+	if ap := s.AdditionalProperties; ap != nil {
+		expr, err := g.expr(ap, indent)
+		if err != nil {
+			return "", err
+		}
+		values = expr
+	}
+	return "Record<string, " + values + ">", nil
+}
+
+// objectBody returns an object type literal of the properties of s.
+func (g *tsGenerator) objectBody(s *Schema, indent string) (string, error) {
+	var sb strings.Builder
+	sb.WriteString("{\n")
+
+	inner := indent + "  "
+	for _, name := range sortedKeys(s.Properties) {
+		prop := s.Properties[name]
+		expr, err := g.expr(&prop, inner)
+		if err != nil {
+			return "", fmt.Errorf("property %q: %w", name, err)
+		}
+
+		optional := "?"
+		if slices.Contains(s.Required, name) {
+			optional = ""
+		}
+
+		tsDocComment(&sb, &prop, inner)
+		fmt.Fprintf(&sb, "%s%s%s: %s;\n", inner, tsPropertyName(name), optional, expr)
+	}
+
+	sb.WriteString(indent + "}")
+	return sb.String(), nil
+}
+
+var tsIdentifierPattern = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+func tsPropertyName(name string) string {
+	if tsIdentifierPattern.MatchString(name) {
+		return name
+	}
+	return strconv.Quote(name)
+}
+
+// tsIdentifier returns name with all characters that are not allowed in a
+// TypeScript identifier replaced by an underscore.
+func tsIdentifier(name string) string {
+	if tsIdentifierPattern.MatchString(name) {
+		return name
+	}
+
+	id := []rune(name)
+	for i, r := range id {
+		if !(r == '_' || r == '$' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (i > 0 && r >= '0' && r <= '9')) {
+			id[i] = '_'
+		}
+	}
+	return string(id)
+}
+
+// tsReserved contains the reserved words and predefined type names of TypeScript,
+// which cannot be used as the name of a type.
+var tsReserved = map[string]bool{
+	"any": true, "bigint": true, "boolean": true, "break": true, "case": true,
+	"catch": true, "class": true, "const": true, "continue": true, "debugger": true,
+	"default": true, "delete": true, "do": true, "else": true, "enum": true,
+	"export": true, "extends": true, "false": true, "finally": true, "for": true,
+	"function": true, "if": true, "implements": true, "import": true, "in": true,
+	"instanceof": true, "interface": true, "let": true, "never": true, "new": true,
+	"null": true, "number": true, "object": true, "package": true, "private": true,
+	"protected": true, "public": true, "return": true, "static": true, "string": true,
+	"super": true, "switch": true, "symbol": true, "this": true, "throw": true,
+	"true": true, "try": true, "typeof": true, "undefined": true, "unknown": true,
+	"var": true, "void": true, "while": true, "with": true, "yield": true,
+}
+
+func tsLiteral(v any) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("invalid literal: %w", err)
+	}
+	return string(b), nil
+}
+
+// tsParen wraps a union or intersection in parentheses.
+func tsParen(expr string) string {
+	if strings.Contains(expr, " | ") || strings.Contains(expr, " & ") {
+		return "(" + expr + ")"
+	}
+	return expr
+}
@@ -0,0 +1,148 @@
+package jsonschema_test
+
+import (
+	"encoding/json"
+	. "jsonschema"
+	"strings"
+	"testing"
+)
+
+func TestGenerateTypeScript(t *testing.T) {
+	const schema = `{
+    "description": "A person.",
+    "type": "object",
+    "properties": {
+        "name": { "type": ["string", "null"], "description": "The full name." },
+        "age": { "type": "integer", "minimum": 0 },
+        "role": { "enum": ["admin", "user"] },
+        "address": { "$ref": "#/$defs/address" },
+        "tags": { "type": "array", "items": { "type": ["string", "number"] } },
+        "point": { "type": "array", "prefixItems": [{ "type": "number" }, { "type": "number" }], "items": false },
+        "labels": { "type": "object", "additionalProperties": { "type": "string" } },
+        "parent": { "oneOf": [{ "$ref": "#" }, { "type": "null" }] },
+        "first-name": { "const": "x" },
+        "meta": {}
+    },
+    "required": ["name", "address"],
+    "$defs": {
+        "address": {
+            "type": "object",
+            "properties": {
+                "street": { "type": "string" },
+                "geo": {
+                    "type": ["object", "null"],
+                    "properties": { "lat": { "type": "number" } },
+                    "required": ["lat"]
+                }
+            },
+            "required": ["street"]
+        },
+        "id": { "type": "string" },
+        "nothing": false
+    }
+}`
+
+	const expected = `export interface address {
+  geo?: {
+    lat: number;
+  } | null;
+  street: string;
+}
+
+export type id = string;
+
+export type nothing = never;
+
+/**
+ * A person.
+ */
+export interface Schema {
+  address: address;
+  age?: number;
+  "first-name"?: "x";
+  labels?: Record<string, string>;
+  meta?: unknown;
+  /**
+   * The full name.
+   */
+  name: string | null;
+  parent?: Schema | null;
+  point?: [number, number];
+  role?: "admin" | "user";
+  tags?: (string | number)[];
+}
+`
+
+	s := &Schema{}
+	if err := json.Unmarshal([]byte(schema), s); err != nil {
+		t.Logf("invalid schema: %s", err)
+		t.FailNow()
+	}
+
+	var sb strings.Builder
+	if err := GenerateTypeScript(s, &sb); err != nil {
+		t.Logf("unexpected error: %s", err)
+		t.FailNow()
+	}
+
+	if sb.String() != expected {
+		t.Errorf("\nhave\n%s\nneed\n%s", sb.String(), expected)
+	}
+
+	t.Run("unsupported reference", func(t *testing.T) {
+		s := &Schema{Items: &Schema{Ref: "other.json"}}
+		err := GenerateTypeScript(s, &strings.Builder{})
+		if err == nil || err.Error() != `schema.GenerateTypeScript: unsupported reference "other.json"` {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("names", func(t *testing.T) {
+		const schema = `{
+    "properties": {
+        "a": { "$ref": "#/$defs/a-b" },
+        "b": { "$ref": "#/$defs/a_b" },
+        "c": { "$ref": "#/$defs/Schema" },
+        "d": { "$ref": "#/$defs/class" },
+        "e": { "$ref": "#" }
+    },
+    "$defs": {
+        "a-b": { "type": "string" },
+        "a_b": { "type": "number" },
+        "Schema": { "type": "boolean" },
+        "class": { "type": "null" }
+    }
+}`
+		const expected = `export type Schema_2 = boolean;
+
+export type a_b = string;
+
+export type a_b_2 = number;
+
+export type _class = null;
+
+export interface Schema {
+  a?: a_b;
+  b?: a_b_2;
+  c?: Schema_2;
+  d?: _class;
+  e?: Schema;
+}
+`
+
+		s := &Schema{}
+		if err := json.Unmarshal([]byte(schema), s); err != nil {
+			t.Logf("invalid schema: %s", err)
+			t.FailNow()
+		}
+
+		var sb strings.Builder
+		if err := GenerateTypeScript(s, &sb); err != nil {
+			t.Logf("unexpected error: %s", err)
+			t.FailNow()
+		}
+		if sb.String() != expected {
+			t.Errorf("\nhave\n%s\nneed\n%s", sb.String(), expected)
+		}
+	})
+}
@@ -0,0 +1,735 @@
+package jsonschema
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ValidationError describes a single failed assertion.
+type ValidationError struct {
+	Keyword          string // The keyword of the failed assertion.
+	KeywordLocation  string // JSON pointer to the keyword, relative to the root schema.
+	InstanceLocation string // JSON pointer to the instance value that failed.
+	Message          string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%q at %q: %s", e.Keyword, e.InstanceLocation, e.Message)
+}
+
+// ValidationErrors is a list of all failed assertions of a validation.
+type ValidationErrors []*ValidationError
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i := range e {
+		msgs[i] = e[i].Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// ValidationResult is the result of a validation. It is encoded using the "basic"
+// output format of the specification, a flat list of all failed assertions:
+//
+//	{
+//	  "valid": false,
+//	  "errors": [
+//	    {"keywordLocation": "/multipleOf", "instanceLocation": "/0", "error": "3 is not a multiple of 2"}
+//	  ]
+//	}
+type ValidationResult struct {
+	Valid  bool
+	Errors ValidationErrors
+}
+
+func (r ValidationResult) MarshalJSON() ([]byte, error) {
+	type outputUnit struct {
+		KeywordLocation  string `json:"keywordLocation"`
+		InstanceLocation string `json:"instanceLocation"`
+		Error            string `json:"error"`
+	}
+
+	out := struct {
+		Valid  bool         `json:"valid"`
+		Errors []outputUnit `json:"errors,omitempty"`
+	}{Valid: r.Valid}
+	for _, e := range r.Errors {
+		out.Errors = append(out.Errors, outputUnit{
+			KeywordLocation:  e.KeywordLocation,
+			InstanceLocation: e.InstanceLocation,
+			Error:            e.Message,
+		})
+	}
+	return json.Marshal(out)
+}
+
+// ValidateConfig configures the validation of ValidateWithConfig.
+type ValidateConfig struct {
+	// StrictFormats fails the validation of a string against a format without a
+	// registered checker. By default, such a format is only an annotation, see
+	// RegisterFormat.
+	StrictFormats bool
+}
+
+// ValidateResult validates the instance against s like Validate, but returns the
+// result instead of an error.
+func (s *Schema) ValidateResult(instance any) ValidationResult {
+	return s.validateResult(ValidateConfig{}, instance)
+}
+
+func (s *Schema) validateResult(config ValidateConfig, instance any) ValidationResult {
+	// The schema is compiled without a loader, references to other documents
+	// cannot be resolved and are reported as failed assertions.
+	c, _ := compileSchema(context.Background(), config, s, nil, true)
+	return *c.Validate(instance)
+}
+
+// Validate validates the instance against s. The instance is expected to be
+// a value as decoded by encoding/json, i.e. one of nil, bool, float64,
+// json.Number, string, []any and map[string]any.
+//
+// Validation does not stop at the first failed assertion. If the instance is
+// invalid, the returned error is of type ValidationErrors, containing all failed
+// assertions. Use Compile to validate many instances against the same schema.
+//
+// The following keywords are validated:
+//   - type, enum and const
+//   - allOf, anyOf, oneOf and not
+//   - if, then and else
+//   - multipleOf, minimum, maximum, exclusiveMinimum and exclusiveMaximum
+//   - minLength, maxLength, pattern and format, see RegisterFormat
+//   - prefixItems, items, minItems, maxItems and uniqueItems
+//   - contains, minContains and maxContains
+//   - properties, patternProperties, additionalProperties and propertyNames
+//   - required, minProperties and maxProperties
+//   - dependentRequired and dependentSchemas
+//   - $ref and $dynamicRef
+//
+// References are followed within the schema, a reference to another document
+// fails the validation, use Compile with a Loader to follow it. The failed
+// assertions of the subschemas of allOf, anyOf and oneOf are reported in
+// addition to the failed applicator.
+func (s *Schema) Validate(instance any) error {
+	return s.ValidateWithConfig(ValidateConfig{}, instance)
+}
+
+// ValidateWithConfig is like Validate, but allows to configure the validation.
+func (s *Schema) ValidateWithConfig(config ValidateConfig, instance any) error {
+	if r := s.validateResult(config, instance); !r.Valid {
+		return r.Errors
+	}
+	return nil
+}
+
+type validator struct {
+	config ValidateConfig
+
+	// patterns caches the compiled patterns by their source, see CompiledSchema.
+	patterns map[string]*regexp.Regexp
+
+	// refs and anchors are the resolved references and the dynamic anchors of
+	// the resources, see CompiledSchema.
+	refs    map[string]compiledRef
+	anchors map[string]map[string]*Schema
+
+	// scope contains the resources entered during the validation, the outermost
+	// first. It is the dynamic scope of $dynamicRef.
+	scope []scopeEntry
+
+	// visiting contains the references being validated, to detect a reference
+	// cycle not consuming the instance.
+	visiting map[refVisit]bool
+}
+
+// refVisit is a schema referenced by $ref or $dynamicRef that is validated
+// against the instance at a location.
+type refVisit struct {
+	schema  *Schema
+	instLoc string
+}
+
+// enter adds the resource identified by uri to the scope, unless it is the
+// innermost resource already. It returns whether the resource was added.
+func (v *validator) enter(resource *Schema, uri *url.URL) bool {
+	if n := len(v.scope); n > 0 && v.scope[n-1].resource == resource {
+		return false
+	}
+	v.scope = append(v.scope, scopeEntry{resource: resource, uri: uri})
+	return true
+}
+
+// leave removes the innermost resource from the scope.
+func (v *validator) leave() {
+	v.scope = v.scope[:len(v.scope)-1]
+}
+
+// base returns the URI of the innermost resource.
+func (v *validator) base() *url.URL {
+	if n := len(v.scope); n > 0 && v.scope[n-1].uri != nil {
+		return v.scope[n-1].uri
+	}
+	return &url.URL{}
+}
+
+// regexp returns the compiled pattern, compiling it on first use.
+func (v *validator) regexp(pattern string) (*regexp.Regexp, error) {
+	if re, ok := v.patterns[pattern]; ok {
+		return re, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	if v.patterns == nil {
+		v.patterns = make(map[string]*regexp.Regexp)
+	}
+	v.patterns[pattern] = re
+	return re, nil
+}
+
+// validate validates the instance against s, kwLoc and instLoc are the
+// locations of s and the instance.
+func (v *validator) validate(s *Schema, instance any, kwLoc, instLoc string) ValidationErrors {
+	// The $id is applied like compile does, so that references are looked up
+	// by the same keys.
+	if s.ID != "" {
+		id, _ := url.Parse(s.ID)
+		if v.enter(s, v.base().ResolveReference(id)) {
+			defer v.leave()
+		}
+	}
+
+	errs := v.validateRef(s, "$ref", s.Ref, instance, kwLoc, instLoc)
+	errs = append(errs, v.validateRef(s, "$dynamicRef", s.DynamicRef, instance, kwLoc, instLoc)...)
+	errs = append(errs, v.validateValue(s, instance, kwLoc, instLoc)...)
+	if obj, ok := instance.(map[string]any); ok {
+		errs = append(errs, v.validateObject(s, obj, kwLoc, instLoc)...)
+	}
+	if arr, ok := instance.([]any); ok {
+		errs = append(errs, v.validateArray(s, arr, kwLoc, instLoc)...)
+	}
+	if num, ok := numberString(instance); ok {
+		errs = append(errs, v.validateNumber(s, num, kwLoc, instLoc)...)
+	}
+	if str, ok := instance.(string); ok {
+		errs = append(errs, v.validateString(s, str, kwLoc, instLoc)...)
+	}
+	errs = append(errs, v.validateApplicators(s, instance, kwLoc, instLoc)...)
+	return append(errs, v.validateConditional(s, instance, kwLoc, instLoc)...)
+}
+
+// validateRef validates the instance against the schema referenced by ref, the
+// value of the keyword $ref or $dynamicRef of s. A $dynamicRef to a
+// $dynamicAnchor is resolved to the outermost resource of the dynamic scope
+// declaring the anchor.
+func (v *validator) validateRef(s *Schema, keyword, ref string, instance any, kwLoc, instLoc string) ValidationErrors {
+	if ref == "" {
+		return nil
+	}
+
+	u, err := url.Parse(ref)
+	if err != nil {
+		return ValidationErrors{{
+			Keyword:          keyword,
+			KeywordLocation:  kwLoc + "/" + keyword,
+			InstanceLocation: instLoc,
+			Message:          fmt.Sprintf("invalid reference %q: %s", ref, err),
+		}}
+	}
+	r, ok := v.refs[v.base().ResolveReference(u).String()]
+	if !ok {
+		r.err = fmt.Errorf("unresolved reference %q", ref)
+	}
+	if r.err != nil {
+		return ValidationErrors{{
+			Keyword:          keyword,
+			KeywordLocation:  kwLoc + "/" + keyword,
+			InstanceLocation: instLoc,
+			Message:          r.err.Error(),
+		}}
+	}
+
+	if name := u.Fragment; keyword == "$dynamicRef" && name != "" && name[0] != '/' && r.schema.DynamicAnchor == name {
+		for _, e := range v.scope {
+			if a := v.anchors[e.uri.String()][name]; a != nil {
+				r.schema, r.resource, r.uri = a, e.resource, e.uri
+				break
+			}
+		}
+	}
+
+	visit := refVisit{schema: r.schema, instLoc: instLoc}
+	if v.visiting[visit] {
+		return nil
+	}
+	if v.visiting == nil {
+		v.visiting = make(map[refVisit]bool)
+	}
+	v.visiting[visit] = true
+	defer delete(v.visiting, visit)
+
+	if v.enter(r.resource, r.uri) {
+		defer v.leave()
+	}
+	return v.validate(r.schema, instance, kwLoc+"/"+keyword, instLoc)
+}
+
+// validateValue validates the keywords applying to instances of any type, i.e.
+// type, enum and const. Values are compared by their canonical encoding.
+func (v *validator) validateValue(s *Schema, instance any, kwLoc, instLoc string) ValidationErrors {
+	var errs ValidationErrors
+	if len(s.Type) > 0 {
+		// An integer is a number, but a number is an integer only without a
+		// fractional part, which InferValueType already takes into account.
+		t := InferValueType(instance)
+		if !slices.Contains(s.Type, t) && !(t == TypeInteger && slices.Contains(s.Type, TypeNumber)) {
+			errs = append(errs, &ValidationError{
+				Keyword:          "type",
+				KeywordLocation:  kwLoc + "/type",
+				InstanceLocation: instLoc,
+				Message:          fmt.Sprintf("have %s, need %q", t, s.Type),
+			})
+		}
+	}
+
+	if s.Enum == nil && s.Const == nil {
+		return errs
+	}
+
+	key, err := canonicalJSON(instance)
+	if err != nil {
+		return errs
+	}
+	equal := func(v any) bool {
+		k, err := canonicalJSON(v)
+		return err == nil && k == key
+	}
+
+	if s.Enum != nil && !slices.ContainsFunc(s.Enum, equal) {
+		errs = append(errs, &ValidationError{
+			Keyword:          "enum",
+			KeywordLocation:  kwLoc + "/enum",
+			InstanceLocation: instLoc,
+			Message:          fmt.Sprintf("%s is not one of the enum values", key),
+		})
+	}
+	if s.Const != nil && !equal(s.Const) {
+		c, _ := canonicalJSON(s.Const)
+		errs = append(errs, &ValidationError{
+			Keyword:          "const",
+			KeywordLocation:  kwLoc + "/const",
+			InstanceLocation: instLoc,
+			Message:          fmt.Sprintf("have %s, need %s", key, c),
+		})
+	}
+	return errs
+}
+
+// validateApplicators validates the instance against the subschemas of allOf,
+// anyOf, oneOf and not.
+func (v *validator) validateApplicators(s *Schema, instance any, kwLoc, instLoc string) ValidationErrors {
+	var errs ValidationErrors
+	for i := range s.AllOf {
+		errs = append(errs, v.validate(&s.AllOf[i], instance, kwLoc+"/allOf/"+strconv.Itoa(i), instLoc)...)
+	}
+
+	for _, keyword := range []string{"anyOf", "oneOf"} {
+		branches := s.AnyOf
+		if keyword == "oneOf" {
+			branches = s.OneOf
+		}
+		if len(branches) == 0 {
+			continue
+		}
+
+		var (
+			valid      []int
+			branchErrs ValidationErrors
+		)
+		for i := range branches {
+			bErrs := v.validate(&branches[i], instance, kwLoc+"/"+keyword+"/"+strconv.Itoa(i), instLoc)
+			if len(bErrs) == 0 {
+				valid = append(valid, i)
+			}
+			branchErrs = append(branchErrs, bErrs...)
+		}
+
+		var msg string
+		switch {
+		case len(valid) == 0:
+			msg = fmt.Sprintf("not valid against any of the %d subschemas", len(branches))
+		case keyword == "oneOf" && len(valid) > 1:
+			msg = fmt.Sprintf("valid against the subschemas %v, need exactly one", valid)
+			branchErrs = nil
+		default:
+			continue
+		}
+
+		errs = append(errs, &ValidationError{
+			Keyword:          keyword,
+			KeywordLocation:  kwLoc + "/" + keyword,
+			InstanceLocation: instLoc,
+			Message:          msg,
+		})
+		errs = append(errs, branchErrs...)
+	}
+
+	if s.Not != nil && len(v.validate(s.Not, instance, kwLoc+"/not", instLoc)) == 0 {
+		msg := "valid against not"
+		if s.Not.IsTrue() {
+			msg = "no value is valid against the false schema"
+		}
+		errs = append(errs, &ValidationError{
+			Keyword:          "not",
+			KeywordLocation:  kwLoc + "/not",
+			InstanceLocation: instLoc,
+			Message:          msg,
+		})
+	}
+	return errs
+}
+
+// validateConditional validates the instance against then if it is valid against
+// if, or against else otherwise. A missing then or else is always valid, an if
+// without then and else has no effect. The failed assertions of if are not
+// reported.
+func (v *validator) validateConditional(s *Schema, instance any, kwLoc, instLoc string) ValidationErrors {
+	if s.If == nil || (s.Then == nil && s.Else == nil) {
+		return nil
+	}
+
+	if len(v.validate(s.If, instance, kwLoc+"/if", instLoc)) == 0 {
+		if s.Then != nil {
+			return v.validate(s.Then, instance, kwLoc+"/then", instLoc)
+		}
+	} else if s.Else != nil {
+		return v.validate(s.Else, instance, kwLoc+"/else", instLoc)
+	}
+	return nil
+}
+
+func (v *validator) validateNumber(s *Schema, num string, kwLoc, instLoc string) ValidationErrors {
+	var errs ValidationErrors
+	if s.MultipleOf != nil {
+		// Both numbers are parsed as exact decimals to prevent floating point
+		// errors, e.g. 0.3 is a multiple of 0.1.
+		n, _ := new(big.Rat).SetString(num)
+		d, ok := new(big.Rat).SetString(string(*s.MultipleOf))
+
+		var msg string
+		if !ok || d.Sign() <= 0 {
+			msg = fmt.Sprintf("invalid divisor %s", *s.MultipleOf)
+		} else if !n.Quo(n, d).IsInt() {
+			msg = fmt.Sprintf("%s is not a multiple of %s", num, *s.MultipleOf)
+		}
+
+		if msg != "" {
+			errs = append(errs, &ValidationError{
+				Keyword:          "multipleOf",
+				KeywordLocation:  kwLoc + "/multipleOf",
+				InstanceLocation: instLoc,
+				Message:          msg,
+			})
+		}
+	}
+
+	n, _ := new(big.Rat).SetString(num)
+	for _, b := range []struct {
+		keyword string
+		bound   *json.Number
+		valid   func(cmp int) bool
+		msg     string
+	}{
+		{"minimum", s.Minimum, func(cmp int) bool { return cmp >= 0 }, "less than"},
+		{"exclusiveMinimum", s.ExclusiveMinimum, func(cmp int) bool { return cmp > 0 }, "not greater than"},
+		{"maximum", s.Maximum, func(cmp int) bool { return cmp <= 0 }, "greater than"},
+		{"exclusiveMaximum", s.ExclusiveMaximum, func(cmp int) bool { return cmp < 0 }, "not less than"},
+	} {
+		if b.bound == nil {
+			continue
+		}
+
+		var msg string
+		if r, ok := new(big.Rat).SetString(string(*b.bound)); !ok {
+			msg = fmt.Sprintf("invalid %s %s", b.keyword, *b.bound)
+		} else if !b.valid(n.Cmp(r)) {
+			msg = fmt.Sprintf("%s is %s %s", num, b.msg, *b.bound)
+		}
+
+		if msg != "" {
+			errs = append(errs, &ValidationError{
+				Keyword:          b.keyword,
+				KeywordLocation:  kwLoc + "/" + b.keyword,
+				InstanceLocation: instLoc,
+				Message:          msg,
+			})
+		}
+	}
+	return errs
+}
+
+// countErrors returns the errors of the minimum and maximum count of a keyword,
+// e.g. minItems and maxItems. noun describes what is counted.
+func countErrors(count int, minimum, maximum *int, minKeyword, maxKeyword, noun, kwLoc, instLoc string) ValidationErrors {
+	var errs ValidationErrors
+	if minimum != nil && count < *minimum {
+		errs = append(errs, &ValidationError{
+			Keyword:          minKeyword,
+			KeywordLocation:  kwLoc + "/" + minKeyword,
+			InstanceLocation: instLoc,
+			Message:          fmt.Sprintf("%d %s, need at least %d", count, noun, *minimum),
+		})
+	}
+	if maximum != nil && count > *maximum {
+		errs = append(errs, &ValidationError{
+			Keyword:          maxKeyword,
+			KeywordLocation:  kwLoc + "/" + maxKeyword,
+			InstanceLocation: instLoc,
+			Message:          fmt.Sprintf("%d %s, need at most %d", count, noun, *maximum),
+		})
+	}
+	return errs
+}
+
+func (v *validator) validateString(s *Schema, str string, kwLoc, instLoc string) ValidationErrors {
+	// The length of a string is the number of its code points.
+	errs := countErrors(utf8.RuneCountInString(str), s.MinLength, s.MaxLength, "minLength", "maxLength", "characters", kwLoc, instLoc)
+	errs = append(errs, v.validateFormat(s, str, kwLoc, instLoc)...)
+	if s.Pattern != nil {
+		var msg string
+		if re, err := v.regexp(*s.Pattern); err != nil {
+			msg = fmt.Sprintf("invalid pattern %q: %s", *s.Pattern, err)
+		} else if !re.MatchString(str) {
+			msg = fmt.Sprintf("%q does not match pattern %q", str, *s.Pattern)
+		}
+
+		if msg != "" {
+			errs = append(errs, &ValidationError{
+				Keyword:          "pattern",
+				KeywordLocation:  kwLoc + "/pattern",
+				InstanceLocation: instLoc,
+				Message:          msg,
+			})
+		}
+	}
+	return errs
+}
+
+func (v *validator) validateArray(s *Schema, arr []any, kwLoc, instLoc string) ValidationErrors {
+	errs := countErrors(len(arr), s.MinItems, s.MaxItems, "minItems", "maxItems", "items", kwLoc, instLoc)
+
+	for i, item := range arr {
+		if i < len(s.PrefixItems) {
+			errs = append(errs, v.validate(&s.PrefixItems[i], item, kwLoc+"/prefixItems/"+strconv.Itoa(i), instLoc+"/"+strconv.Itoa(i))...)
+			continue
+		}
+		if s.Items == nil {
+			break
+		}
+		if s.Items.IsFalse() {
+			errs = append(errs, &ValidationError{
+				Keyword:          "items",
+				KeywordLocation:  kwLoc + "/items",
+				InstanceLocation: instLoc,
+				Message:          fmt.Sprintf("%d items, need at most %d", len(arr), len(s.PrefixItems)),
+			})
+			break
+		}
+		errs = append(errs, v.validate(s.Items, item, kwLoc+"/items", instLoc+"/"+strconv.Itoa(i))...)
+	}
+
+	if s.UniqueItemsOr(false) {
+		// Items are compared by their canonical encoding, so that numbers and
+		// objects are equal regardless of their representation and key order.
+		seen := make(map[string]int, len(arr))
+		for i, item := range arr {
+			key, err := canonicalJSON(item)
+			if err != nil {
+				continue
+			}
+			if j, ok := seen[key]; ok {
+				errs = append(errs, &ValidationError{
+					Keyword:          "uniqueItems",
+					KeywordLocation:  kwLoc + "/uniqueItems",
+					InstanceLocation: instLoc,
+					Message:          fmt.Sprintf("items %d and %d are equal", j, i),
+				})
+				break
+			}
+			seen[key] = i
+		}
+	}
+
+	if s.Contains != nil {
+		errs = append(errs, v.validateContains(s, arr, kwLoc, instLoc)...)
+	}
+	return errs
+}
+
+// validateContains counts the items that are valid against contains and checks
+// the count against minContains, which defaults to 1, and maxContains. The failed
+// assertions of the items are not reported.
+func (v *validator) validateContains(s *Schema, arr []any, kwLoc, instLoc string) ValidationErrors {
+	var matched []int
+	for i, item := range arr {
+		if len(v.validate(s.Contains, item, kwLoc+"/contains", instLoc+"/"+strconv.Itoa(i))) == 0 {
+			matched = append(matched, i)
+		}
+	}
+
+	minContains, keyword := 1, "contains"
+	if s.MinContains != nil {
+		minContains, keyword = *s.MinContains, "minContains"
+	}
+
+	var msg string
+	switch {
+	case len(matched) < minContains:
+		msg = fmt.Sprintf("%d items %v are valid against contains, need at least %d", len(matched), matched, minContains)
+	case s.MaxContains != nil && len(matched) > *s.MaxContains:
+		keyword = "maxContains"
+		msg = fmt.Sprintf("%d items %v are valid against contains, need at most %d", len(matched), matched, *s.MaxContains)
+	default:
+		return nil
+	}
+
+	return ValidationErrors{{
+		Keyword:          keyword,
+		KeywordLocation:  kwLoc + "/" + keyword,
+		InstanceLocation: instLoc,
+		Message:          msg,
+	}}
+}
+
+func (v *validator) validateObject(s *Schema, obj map[string]any, kwLoc, instLoc string) ValidationErrors {
+	errs := countErrors(len(obj), s.MinProperties, s.MaxProperties, "minProperties", "maxProperties", "properties", kwLoc, instLoc)
+
+	var missing []string
+	for _, name := range s.Required {
+		if _, ok := obj[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		errs = append(errs, &ValidationError{
+			Keyword:          "required",
+			KeywordLocation:  kwLoc + "/required",
+			InstanceLocation: instLoc,
+			Message:          fmt.Sprintf("missing properties %q", missing),
+		})
+	}
+
+	for _, name := range sortedKeys(obj) {
+		propLoc := instLoc + "/" + escapeToken(name)
+
+		if s.PropertyNames != nil {
+			// The location of a property name is the location of the property.
+			errs = append(errs, v.validate(s.PropertyNames, name, kwLoc+"/propertyNames", propLoc)...)
+		}
+
+		matched := false
+		if ps, ok := s.Properties[name]; ok {
+			matched = true
+			errs = append(errs, v.validate(&ps, obj[name], kwLoc+"/properties/"+escapeToken(name), propLoc)...)
+		}
+
+		for _, pattern := range sortedKeys(s.PatternProperties) {
+			re, err := v.regexp(pattern)
+			if err != nil {
+				errs = append(errs, &ValidationError{
+					Keyword:          "patternProperties",
+					KeywordLocation:  kwLoc + "/patternProperties/" + escapeToken(pattern),
+					InstanceLocation: propLoc,
+					Message:          fmt.Sprintf("invalid pattern %q: %s", pattern, err),
+				})
+				continue
+			}
+			if re.MatchString(name) {
+				matched = true
+				ps := s.PatternProperties[pattern]
+				errs = append(errs, v.validate(&ps, obj[name], kwLoc+"/patternProperties/"+escapeToken(pattern), propLoc)...)
+			}
+		}
+
+		switch {
+		case matched || s.AdditionalProperties == nil:
+		case s.AdditionalProperties.IsFalse():
+			errs = append(errs, &ValidationError{
+				Keyword:          "additionalProperties",
+				KeywordLocation:  kwLoc + "/additionalProperties",
+				InstanceLocation: instLoc,
+				Message:          fmt.Sprintf("property %q is not allowed", name),
+			})
+		default:
+			errs = append(errs, v.validate(s.AdditionalProperties, obj[name], kwLoc+"/additionalProperties", propLoc)...)
+		}
+	}
+
+	for _, name := range sortedKeys(s.DependentRequired) {
+		if _, ok := obj[name]; !ok {
+			continue
+		}
+
+		var missing []string
+		for _, dep := range s.DependentRequired[name] {
+			if _, ok := obj[dep]; !ok {
+				missing = append(missing, dep)
+			}
+		}
+
+		if len(missing) > 0 {
+			errs = append(errs, &ValidationError{
+				Keyword:          "dependentRequired",
+				KeywordLocation:  kwLoc + "/dependentRequired/" + escapeToken(name),
+				InstanceLocation: instLoc,
+				Message:          fmt.Sprintf("property %q requires missing properties %q", name, missing),
+			})
+		}
+	}
+
+	// A dependent schema applies to the whole object, not just the property.
+	for _, name := range sortedKeys(s.DependentSchemas) {
+		if _, ok := obj[name]; !ok {
+			continue
+		}
+
+		dep := s.DependentSchemas[name]
+		errs = append(errs, v.validate(&dep, obj, kwLoc+"/dependentSchemas/"+escapeToken(name), instLoc)...)
+	}
+	return errs
+}
+
+// numberString returns the decimal representation of a numeric instance. Floats
+// are formatted using the shortest representation that parses to the same value.
+func numberString(instance any) (string, bool) {
+	switch n := instance.(type) {
+	case float64:
+		if math.IsInf(n, 0) || math.IsNaN(n) {
+			return "", false
+		}
+		return strconv.FormatFloat(n, 'g', -1, 64), true
+	case json.Number:
+		if _, ok := new(big.Rat).SetString(string(n)); ok {
+			return string(n), true
+		}
+	}
+	return "", false
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
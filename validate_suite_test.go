@@ -0,0 +1,155 @@
+package jsonschema_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	. "jsonschema"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// The JSON Schema Test Suite (https://github.com/json-schema-org/JSON-Schema-Test-Suite)
+// is not part of the repository. Clone it into testdata or point the environment
+// variable to a checkout to run it:
+//
+//	JSON_SCHEMA_TEST_SUITE=/path/to/JSON-Schema-Test-Suite go test -run TestSchema_Validate_Suite
+const suiteEnv = "JSON_SCHEMA_TEST_SUITE"
+
+// suiteRemote is the base URI of the documents in the remotes directory of the
+// suite, which are referenced by the tests of refRemote.json and others.
+const suiteRemote = "http://localhost:1234/"
+
+// suiteSkip lists the test files of the draft 2020-12 suite that are not run,
+// because the validator does not support the tested feature yet. A file is
+// identified by its name, a single test group by its name and description
+// separated by a slash. Remove an entry once the feature is supported.
+var suiteSkip = map[string]string{
+	"defs.json":                  "validates against the meta-schema",
+	"format.json":                "known formats are asserted by default",
+	"maxContains.json":           "integer keywords with a decimal value are not supported",
+	"minContains.json":           "integer keywords with a decimal value are not supported",
+	"unevaluatedItems.json":      "keyword not validated",
+	"unevaluatedProperties.json": "keyword not validated",
+	"vocabulary.json":            "vocabularies are not supported",
+	"dynamicRef.json/strict-tree schema, guards against misspelled properties":    "keyword unevaluatedProperties not validated",
+	"id.json/Invalid use of fragments in location-independent $id":                "validates against the meta-schema",
+	"id.json/Valid use of empty fragments in location-independent $id":            "validates against the meta-schema",
+	"id.json/Unnormalized $ids are allowed but discouraged":                       "validates against the meta-schema",
+	"not.json/collect annotations inside a 'not', even if collection is disabled": "keyword unevaluatedProperties not validated",
+	"ref.json/ref creates new scope when adjacent to keywords":                    "keyword unevaluatedProperties not validated",
+	"ref.json/remote ref, containing refs itself":                                 "validates against the meta-schema",
+	"maxItems.json/maxItems validation with a decimal":                            "integer keywords with a decimal value are not supported",
+	"maxLength.json/maxLength validation with a decimal":                          "integer keywords with a decimal value are not supported",
+	"maxProperties.json/maxProperties validation with a decimal":                  "integer keywords with a decimal value are not supported",
+	"minItems.json/minItems validation with a decimal":                            "integer keywords with a decimal value are not supported",
+	"minLength.json/minLength validation with a decimal":                          "integer keywords with a decimal value are not supported",
+	"minProperties.json/minProperties validation with a decimal":                  "integer keywords with a decimal value are not supported",
+}
+
+type suiteGroup struct {
+	Description string          `json:"description"`
+	Schema      json.RawMessage `json:"schema"`
+	Tests       []struct {
+		Description string          `json:"description"`
+		Data        json.RawMessage `json:"data"`
+		Valid       bool            `json:"valid"`
+	} `json:"tests"`
+}
+
+func TestSchema_Validate_Suite(t *testing.T) {
+	root := os.Getenv(suiteEnv)
+	if root == "" {
+		root = filepath.Join("testdata", "JSON-Schema-Test-Suite")
+	}
+	if _, err := os.Stat(root); err != nil {
+		t.Skipf("JSON Schema Test Suite not found, set %s to run it", suiteEnv)
+	}
+
+	files, err := filepath.Glob(filepath.Join(root, "tests", "draft2020-12", "*.json"))
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no test files found in %s", root)
+	}
+
+	loader := suiteLoader(filepath.Join(root, "remotes"))
+	for _, file := range files {
+		name := filepath.Base(file)
+		t.Run(name, func(t *testing.T) {
+			if reason, ok := suiteSkip[name]; ok {
+				t.Skip(reason)
+			}
+
+			b, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var groups []suiteGroup
+			if err = json.Unmarshal(b, &groups); err != nil {
+				t.Fatalf("invalid test file: %s", err)
+			}
+
+			for _, group := range groups {
+				runSuiteGroup(t, loader, name, group)
+			}
+		})
+	}
+}
+
+// suiteLoader returns a Loader that serves the documents below dir by their
+// location relative to suiteRemote.
+func suiteLoader(dir string) Loader {
+	return LoaderFunc(func(_ context.Context, uri *url.URL) (*Schema, error) {
+		if uri.Scheme != "http" || uri.Host != "localhost:1234" {
+			return nil, UnsupportedURI
+		}
+
+		b, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(uri.Path)))
+		if err != nil {
+			return nil, err
+		}
+
+		s := &Schema{}
+		if err = json.Unmarshal(b, s); err != nil {
+			return nil, err
+		}
+
+		*uri = url.URL{Fragment: uri.Fragment}
+		return s, nil
+	})
+}
+
+func runSuiteGroup(t *testing.T, loader Loader, file string, group suiteGroup) {
+	t.Run(group.Description, func(t *testing.T) {
+		if reason, ok := suiteSkip[file+"/"+group.Description]; ok {
+			t.Skip(reason)
+		}
+
+		schema := &Schema{}
+		if err := json.Unmarshal(group.Schema, schema); err != nil {
+			t.Fatalf("invalid schema: %s", err)
+		}
+
+		c, err := Compile(context.Background(), schema, loader)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, test := range group.Tests {
+			d := json.NewDecoder(bytes.NewReader(test.Data))
+			d.UseNumber()
+
+			var instance any
+			if err := d.Decode(&instance); err != nil {
+				t.Errorf("%s: invalid instance: %s", test.Description, err)
+				continue
+			}
+
+			if r := c.Validate(instance); r.Valid != test.Valid {
+				t.Errorf("%s: have valid=%t, need valid=%t: %v", test.Description, r.Valid, test.Valid, r.Errors)
+			}
+		}
+	})
+}
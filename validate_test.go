@@ -0,0 +1,729 @@
+package jsonschema_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	. "jsonschema"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+type validationTest struct {
+	instance string
+	errs     ValidationErrors
+}
+
+func runValidationTests(t *testing.T, schema *Schema, tests []validationTest) {
+	t.Helper()
+	for i, test := range tests {
+		var instance any
+		if err := json.Unmarshal([]byte(test.instance), &instance); err != nil {
+			t.Errorf("test #%d: invalid instance: %s", i, err)
+			continue
+		}
+
+		err := schema.Validate(instance)
+		if test.errs == nil {
+			if err != nil {
+				t.Errorf("test #%d: unexpected error: %s", i, err)
+			}
+			continue
+		}
+
+		var errs ValidationErrors
+		if !errors.As(err, &errs) {
+			t.Errorf("test #%d: expected ValidationErrors, got %v", i, err)
+		} else if !reflect.DeepEqual(errs, test.errs) {
+			t.Errorf("test #%d:\nhave %s\nneed %s", i, errs, test.errs)
+		}
+	}
+}
+
+func TestSchema_Validate_DependentRequired(t *testing.T) {
+	loader := NewEmbeddedLoader(testdataFS)
+
+	uri, _ := url.Parse("file:///testdata/miscellaneous-examples/conditional-validation-dependentRequired.schema.json")
+	schema, _ := loader.Load(nil, uri)
+
+	runValidationTests(t, schema, []validationTest{
+		{instance: `{}`},
+		{instance: `{"bar": "baz"}`},
+		{instance: `{"foo": true, "bar": "baz"}`},
+		{instance: `"not an object"`, errs: ValidationErrors{{
+			Keyword:          "type",
+			KeywordLocation:  "/type",
+			InstanceLocation: "",
+			Message:          `have string, need ["object"]`,
+		}}},
+		{instance: `{"foo": true}`, errs: ValidationErrors{{
+			Keyword:          "dependentRequired",
+			KeywordLocation:  "/dependentRequired/foo",
+			InstanceLocation: "",
+			Message:          `property "foo" requires missing properties ["bar"]`,
+		}}},
+	})
+
+	schema = &Schema{DependentRequired: map[string][]string{
+		"a/b": {"c", "d", "e"},
+		"c":   {"e"},
+	}}
+
+	runValidationTests(t, schema, []validationTest{
+		{instance: `{"a/b": 1, "c": 2, "d": 3, "e": 4}`},
+		{instance: `{"a/b": 1, "c": 2}`, errs: ValidationErrors{
+			{
+				Keyword:         "dependentRequired",
+				KeywordLocation: "/dependentRequired/a~1b",
+				Message:         `property "a/b" requires missing properties ["d" "e"]`,
+			},
+			{
+				Keyword:         "dependentRequired",
+				KeywordLocation: "/dependentRequired/c",
+				Message:         `property "c" requires missing properties ["e"]`,
+			},
+		}},
+	})
+}
+
+func TestSchema_Validate_MultipleOf(t *testing.T) {
+	runValidationTests(t, &Schema{MultipleOf: ptr(json.Number("0.1"))}, []validationTest{
+		{instance: `0.1`},
+		{instance: `0.3`},
+		{instance: `-0.7`},
+		{instance: `12`},
+		{instance: `1e-1`},
+		{instance: `"0.15"`},
+		{instance: `0.15`, errs: ValidationErrors{{
+			Keyword:         "multipleOf",
+			KeywordLocation: "/multipleOf",
+			Message:         "0.15 is not a multiple of 0.1",
+		}}},
+	})
+
+	runValidationTests(t, &Schema{MultipleOf: ptr(json.Number("1.5"))}, []validationTest{
+		{instance: `4.5`},
+		{instance: `0`},
+		{instance: `4`, errs: ValidationErrors{{
+			Keyword:         "multipleOf",
+			KeywordLocation: "/multipleOf",
+			Message:         "4 is not a multiple of 1.5",
+		}}},
+	})
+
+	runValidationTests(t, &Schema{MultipleOf: ptr(json.Number("0"))}, []validationTest{
+		{instance: `1`, errs: ValidationErrors{{
+			Keyword:         "multipleOf",
+			KeywordLocation: "/multipleOf",
+			Message:         "invalid divisor 0",
+		}}},
+	})
+
+	if err := (&Schema{MultipleOf: ptr(json.Number("0.01"))}).Validate(json.Number("19.99")); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+}
+
+func TestSchema_Validate_UniqueItems(t *testing.T) {
+	duplicate := func(i, j int) ValidationErrors {
+		return ValidationErrors{{
+			Keyword:         "uniqueItems",
+			KeywordLocation: "/uniqueItems",
+			Message:         fmt.Sprintf("items %d and %d are equal", i, j),
+		}}
+	}
+
+	runValidationTests(t, &Schema{UniqueItems: ptr(true)}, []validationTest{
+		{instance: `[]`},
+		{instance: `[1, "1", true, null, [1], {"a": 1}]`},
+		{instance: `[{"a": 1, "b": 2}, {"a": 1, "b": 3}]`},
+		{instance: `[1, 2, 1.0]`, errs: duplicate(0, 2)},
+		{instance: `[null, null, 1, 1]`, errs: duplicate(0, 1)},
+		{instance: `[[1, "a"], [1.0, "a"]]`, errs: duplicate(0, 1)},
+		{instance: `[{"a": 1, "b": [2]}, {"b": [2.0], "a": 1}]`, errs: duplicate(0, 1)},
+		{instance: `{"a": 1, "b": 1}`},
+	})
+
+	runValidationTests(t, &Schema{UniqueItems: ptr(false)}, []validationTest{
+		{instance: `[1, 1]`},
+	})
+
+	err := (&Schema{UniqueItems: ptr(true)}).Validate([]any{json.Number("1e2"), float64(100)})
+	if !reflect.DeepEqual(err, duplicate(0, 1)) {
+		t.Errorf("have %v, need %v", err, duplicate(0, 1))
+	}
+}
+
+func TestSchema_Validate_Pattern(t *testing.T) {
+	runValidationTests(t, &Schema{Pattern: ptr("^[a-z]+$")}, []validationTest{
+		{instance: `"abc"`},
+		{instance: `12`},
+		{instance: `"aBc"`, errs: ValidationErrors{{
+			Keyword:         "pattern",
+			KeywordLocation: "/pattern",
+			Message:         `"aBc" does not match pattern "^[a-z]+$"`,
+		}}},
+	})
+
+	runValidationTests(t, &Schema{Pattern: ptr("(")}, []validationTest{
+		{instance: `"a"`, errs: ValidationErrors{{
+			Keyword:         "pattern",
+			KeywordLocation: "/pattern",
+			Message:         "invalid pattern \"(\": error parsing regexp: missing closing ): `(`",
+		}}},
+	})
+}
+
+func TestSchema_Validate_DependentSchemas(t *testing.T) {
+	schema := &Schema{
+		DependentSchemas: map[string]Schema{
+			"credit_card": {
+				DependentRequired: map[string][]string{"credit_card": {"billing_address"}},
+			},
+			"a/b": {
+				DependentSchemas: map[string]Schema{
+					"total": {DependentRequired: map[string][]string{"total": {"currency"}}},
+				},
+			},
+		},
+	}
+
+	runValidationTests(t, schema, []validationTest{
+		{instance: `{}`},
+		{instance: `{"billing_address": "x"}`},
+		{instance: `{"credit_card": 1, "billing_address": "x"}`},
+		{instance: `{"total": 1}`},
+		{instance: `["credit_card"]`},
+		{instance: `{"credit_card": 1}`, errs: ValidationErrors{{
+			Keyword:         "dependentRequired",
+			KeywordLocation: "/dependentSchemas/credit_card/dependentRequired/credit_card",
+			Message:         `property "credit_card" requires missing properties ["billing_address"]`,
+		}}},
+		{instance: `{"a/b": 1, "total": 1, "credit_card": 1}`, errs: ValidationErrors{
+			{
+				Keyword:         "dependentRequired",
+				KeywordLocation: "/dependentSchemas/a~1b/dependentSchemas/total/dependentRequired/total",
+				Message:         `property "total" requires missing properties ["currency"]`,
+			},
+			{
+				Keyword:         "dependentRequired",
+				KeywordLocation: "/dependentSchemas/credit_card/dependentRequired/credit_card",
+				Message:         `property "credit_card" requires missing properties ["billing_address"]`,
+			},
+		}},
+	})
+}
+
+func TestSchema_Validate_IfThenElse(t *testing.T) {
+	var (
+		even     = Schema{MultipleOf: ptr(json.Number("2"))}
+		byThree  = Schema{MultipleOf: ptr(json.Number("3"))}
+		byFive   = Schema{MultipleOf: ptr(json.Number("5"))}
+		errThree = &ValidationError{Keyword: "multipleOf", KeywordLocation: "/then/multipleOf", Message: "4 is not a multiple of 3"}
+		errFive  = &ValidationError{Keyword: "multipleOf", KeywordLocation: "/else/multipleOf", Message: "3 is not a multiple of 5"}
+	)
+
+	tests := map[string]struct {
+		schema *Schema
+		tests  []validationTest
+	}{
+		"if then": {schema: &Schema{If: &even, Then: &byThree}, tests: []validationTest{
+			{instance: `6`},
+			{instance: `3`},
+			{instance: `4`, errs: ValidationErrors{errThree}},
+		}},
+		"if else": {schema: &Schema{If: &even, Else: &byFive}, tests: []validationTest{
+			{instance: `4`},
+			{instance: `5`},
+			{instance: `3`, errs: ValidationErrors{errFive}},
+		}},
+		"if then else": {schema: &Schema{If: &even, Then: &byThree, Else: &byFive}, tests: []validationTest{
+			{instance: `6`},
+			{instance: `5`},
+			{instance: `4`, errs: ValidationErrors{errThree}},
+			{instance: `3`, errs: ValidationErrors{errFive}},
+		}},
+		"if only": {schema: &Schema{If: &even}, tests: []validationTest{
+			{instance: `3`},
+			{instance: `4`},
+		}},
+		"then and else without if": {schema: &Schema{Then: &byThree, Else: &byFive}, tests: []validationTest{
+			{instance: `4`},
+		}},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			runValidationTests(t, test.schema, test.tests)
+		})
+	}
+}
+
+func TestSchema_Validate_Contains(t *testing.T) {
+	even := &Schema{MultipleOf: ptr(json.Number("2"))}
+
+	tests := map[string]struct {
+		schema *Schema
+		tests  []validationTest
+	}{
+		"contains": {schema: &Schema{Contains: even}, tests: []validationTest{
+			{instance: `[1, 2]`},
+			{instance: `{"a": 1}`},
+			{instance: `[1, 3]`, errs: ValidationErrors{{
+				Keyword:         "contains",
+				KeywordLocation: "/contains",
+				Message:         "0 items [] are valid against contains, need at least 1",
+			}}},
+			{instance: `[]`, errs: ValidationErrors{{
+				Keyword:         "contains",
+				KeywordLocation: "/contains",
+				Message:         "0 items [] are valid against contains, need at least 1",
+			}}},
+		}},
+		"minContains": {schema: &Schema{Contains: even, MinContains: ptr(2)}, tests: []validationTest{
+			{instance: `[2, 3, 4]`},
+			{instance: `[1, 2, 3]`, errs: ValidationErrors{{
+				Keyword:         "minContains",
+				KeywordLocation: "/minContains",
+				Message:         "1 items [1] are valid against contains, need at least 2",
+			}}},
+		}},
+		"minContains zero": {schema: &Schema{Contains: even, MinContains: ptr(0)}, tests: []validationTest{
+			{instance: `[]`},
+			{instance: `[1]`},
+		}},
+		"maxContains": {schema: &Schema{Contains: even, MaxContains: ptr(1)}, tests: []validationTest{
+			{instance: `[1, 2]`},
+			{instance: `[2, 3, 4]`, errs: ValidationErrors{{
+				Keyword:         "maxContains",
+				KeywordLocation: "/maxContains",
+				Message:         "2 items [0 2] are valid against contains, need at most 1",
+			}}},
+		}},
+		"without contains": {schema: &Schema{MinContains: ptr(2), MaxContains: ptr(0)}, tests: []validationTest{
+			{instance: `[2]`},
+		}},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			runValidationTests(t, test.schema, test.tests)
+		})
+	}
+}
+
+func TestSchema_Validate_Type(t *testing.T) {
+	typeErr := func(have string, need ...Type) ValidationErrors {
+		return ValidationErrors{{
+			Keyword:         "type",
+			KeywordLocation: "/type",
+			Message:         fmt.Sprintf("have %s, need %q", have, TypeSet(need)),
+		}}
+	}
+
+	tests := map[string]struct {
+		schema *Schema
+		tests  []validationTest
+	}{
+		"integer": {schema: &Schema{Type: TypeSet{TypeInteger}}, tests: []validationTest{
+			{instance: `1`},
+			{instance: `1.0`},
+			{instance: `1.5`, errs: typeErr("number", TypeInteger)},
+			{instance: `"1"`, errs: typeErr("string", TypeInteger)},
+		}},
+		"number": {schema: &Schema{Type: TypeSet{TypeNumber}}, tests: []validationTest{
+			{instance: `1`},
+			{instance: `1.5`},
+			{instance: `null`, errs: typeErr("null", TypeNumber)},
+		}},
+		"nullable string": {schema: &Schema{Type: TypeSet{TypeString, TypeNull}}, tests: []validationTest{
+			{instance: `"a"`},
+			{instance: `null`},
+			{instance: `[]`, errs: typeErr("array", TypeString, TypeNull)},
+			{instance: `{}`, errs: typeErr("object", TypeString, TypeNull)},
+			{instance: `false`, errs: typeErr("boolean", TypeString, TypeNull)},
+		}},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			runValidationTests(t, test.schema, test.tests)
+		})
+	}
+}
+
+func TestSchema_Validate_EnumConst(t *testing.T) {
+	runValidationTests(t, &Schema{Enum: []any{"a", json.Number("1"), nil, map[string]any{"b": []any{2.0}}}}, []validationTest{
+		{instance: `"a"`},
+		{instance: `1.0`},
+		{instance: `null`},
+		{instance: `{"b": [2]}`},
+		{instance: `true`, errs: ValidationErrors{{
+			Keyword:         "enum",
+			KeywordLocation: "/enum",
+			Message:         "true is not one of the enum values",
+		}}},
+		{instance: `{"b": [2], "c": 3}`, errs: ValidationErrors{{
+			Keyword:         "enum",
+			KeywordLocation: "/enum",
+			Message:         `{"b":[2],"c":3} is not one of the enum values`,
+		}}},
+	})
+
+	runValidationTests(t, &Schema{Enum: []any{}}, []validationTest{
+		{instance: `null`, errs: ValidationErrors{{
+			Keyword:         "enum",
+			KeywordLocation: "/enum",
+			Message:         "null is not one of the enum values",
+		}}},
+	})
+
+	runValidationTests(t, &Schema{Const: []any{1.0, "a"}}, []validationTest{
+		{instance: `[1.0, "a"]`},
+		{instance: `["a", 1]`, errs: ValidationErrors{{
+			Keyword:         "const",
+			KeywordLocation: "/const",
+			Message:         `have ["a",1], need [1,"a"]`,
+		}}},
+	})
+}
+
+func TestSchema_Validate_NumericBounds(t *testing.T) {
+	schema := &Schema{
+		Minimum:          ptr(json.Number("0")),
+		ExclusiveMaximum: ptr(json.Number("1e1")),
+	}
+	runValidationTests(t, schema, []validationTest{
+		{instance: `0`},
+		{instance: `9.99`},
+		{instance: `"100"`},
+		{instance: `-0.5`, errs: ValidationErrors{{
+			Keyword:         "minimum",
+			KeywordLocation: "/minimum",
+			Message:         "-0.5 is less than 0",
+		}}},
+		{instance: `10`, errs: ValidationErrors{{
+			Keyword:         "exclusiveMaximum",
+			KeywordLocation: "/exclusiveMaximum",
+			Message:         "10 is not less than 1e1",
+		}}},
+	})
+
+	schema = &Schema{
+		ExclusiveMinimum: ptr(json.Number("0.1")),
+		Maximum:          ptr(json.Number("0.3")),
+	}
+	runValidationTests(t, schema, []validationTest{
+		{instance: `0.30`},
+		{instance: `0.1`, errs: ValidationErrors{{
+			Keyword:         "exclusiveMinimum",
+			KeywordLocation: "/exclusiveMinimum",
+			Message:         "0.1 is not greater than 0.1",
+		}}},
+		{instance: `0.31`, errs: ValidationErrors{{
+			Keyword:         "maximum",
+			KeywordLocation: "/maximum",
+			Message:         "0.31 is greater than 0.3",
+		}}},
+	})
+}
+
+func TestSchema_Validate_Counts(t *testing.T) {
+	tests := map[string]struct {
+		schema *Schema
+		tests  []validationTest
+	}{
+		"length": {schema: &Schema{MinLength: ptr(2), MaxLength: ptr(3)}, tests: []validationTest{
+			{instance: `"ab"`},
+			{instance: `"äöü"`},
+			{instance: `1`},
+			{instance: `"a"`, errs: ValidationErrors{{
+				Keyword:         "minLength",
+				KeywordLocation: "/minLength",
+				Message:         "1 characters, need at least 2",
+			}}},
+			{instance: `"abcd"`, errs: ValidationErrors{{
+				Keyword:         "maxLength",
+				KeywordLocation: "/maxLength",
+				Message:         "4 characters, need at most 3",
+			}}},
+		}},
+		"items": {schema: &Schema{MinItems: ptr(1), MaxItems: ptr(1)}, tests: []validationTest{
+			{instance: `[null]`},
+			{instance: `[]`, errs: ValidationErrors{{
+				Keyword:         "minItems",
+				KeywordLocation: "/minItems",
+				Message:         "0 items, need at least 1",
+			}}},
+			{instance: `[1, 2]`, errs: ValidationErrors{{
+				Keyword:         "maxItems",
+				KeywordLocation: "/maxItems",
+				Message:         "2 items, need at most 1",
+			}}},
+		}},
+		"properties": {schema: &Schema{MinProperties: ptr(1), MaxProperties: ptr(2)}, tests: []validationTest{
+			{instance: `{"a": 1}`},
+			{instance: `[]`},
+			{instance: `{}`, errs: ValidationErrors{{
+				Keyword:         "minProperties",
+				KeywordLocation: "/minProperties",
+				Message:         "0 properties, need at least 1",
+			}}},
+			{instance: `{"a": 1, "b": 2, "c": 3}`, errs: ValidationErrors{{
+				Keyword:         "maxProperties",
+				KeywordLocation: "/maxProperties",
+				Message:         "3 properties, need at most 2",
+			}}},
+		}},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			runValidationTests(t, test.schema, test.tests)
+		})
+	}
+}
+
+func TestSchema_Validate_Items(t *testing.T) {
+	schema := &Schema{
+		PrefixItems: []Schema{{Type: TypeSet{TypeString}}, True},
+		Items:       &Schema{Type: TypeSet{TypeInteger}},
+	}
+	runValidationTests(t, schema, []validationTest{
+		{instance: `[]`},
+		{instance: `["a", null, 1, 2]`},
+		{instance: `[1, null, 1.5]`, errs: ValidationErrors{
+			{
+				Keyword:          "type",
+				KeywordLocation:  "/prefixItems/0/type",
+				InstanceLocation: "/0",
+				Message:          `have integer, need ["string"]`,
+			},
+			{
+				Keyword:          "type",
+				KeywordLocation:  "/items/type",
+				InstanceLocation: "/2",
+				Message:          `have number, need ["integer"]`,
+			},
+		}},
+	})
+
+	runValidationTests(t, &Schema{PrefixItems: []Schema{True}, Items: &False}, []validationTest{
+		{instance: `[1]`},
+		{instance: `[1, 2, 3]`, errs: ValidationErrors{{
+			Keyword:         "items",
+			KeywordLocation: "/items",
+			Message:         "3 items, need at most 1",
+		}}},
+	})
+}
+
+func TestSchema_Validate_Properties(t *testing.T) {
+	schema := &Schema{
+		Properties: map[string]Schema{
+			"a/b": {Type: TypeSet{TypeString}},
+			"x_1": {Minimum: ptr(json.Number("1"))},
+		},
+		PatternProperties: map[string]Schema{
+			"^x_": {Type: TypeSet{TypeInteger}},
+		},
+		AdditionalProperties: &False,
+		PropertyNames:        &Schema{MaxLength: ptr(3)},
+		Required:             []string{"a/b", "c"},
+	}
+
+	runValidationTests(t, schema, []validationTest{
+		{instance: `{"a/b": "", "c": null}`, errs: ValidationErrors{{
+			Keyword:         "additionalProperties",
+			KeywordLocation: "/additionalProperties",
+			Message:         `property "c" is not allowed`,
+		}}},
+		{instance: `{"a/b": "", "x_1": 1, "x_2": 2}`, errs: ValidationErrors{{
+			Keyword:         "required",
+			KeywordLocation: "/required",
+			Message:         `missing properties ["c"]`,
+		}}},
+		{instance: `{"a/b": 1, "x_1": 0.5, "x_10": 10}`, errs: ValidationErrors{
+			{
+				Keyword:         "required",
+				KeywordLocation: "/required",
+				Message:         `missing properties ["c"]`,
+			},
+			{
+				Keyword:          "type",
+				KeywordLocation:  "/properties/a~1b/type",
+				InstanceLocation: "/a~1b",
+				Message:          `have integer, need ["string"]`,
+			},
+			{
+				Keyword:          "minimum",
+				KeywordLocation:  "/properties/x_1/minimum",
+				InstanceLocation: "/x_1",
+				Message:          "0.5 is less than 1",
+			},
+			{
+				Keyword:          "type",
+				KeywordLocation:  "/patternProperties/^x_/type",
+				InstanceLocation: "/x_1",
+				Message:          `have number, need ["integer"]`,
+			},
+			{
+				Keyword:          "maxLength",
+				KeywordLocation:  "/propertyNames/maxLength",
+				InstanceLocation: "/x_10",
+				Message:          "4 characters, need at most 3",
+			},
+		}},
+	})
+
+	schema = &Schema{
+		Properties:           map[string]Schema{"a": True},
+		AdditionalProperties: &Schema{Type: TypeSet{TypeBoolean}},
+	}
+	runValidationTests(t, schema, []validationTest{
+		{instance: `{"a": 1, "b": true}`},
+		{instance: `{"a": 1, "b": 1}`, errs: ValidationErrors{{
+			Keyword:          "type",
+			KeywordLocation:  "/additionalProperties/type",
+			InstanceLocation: "/b",
+			Message:          `have integer, need ["boolean"]`,
+		}}},
+	})
+}
+
+func TestSchema_Validate_Applicators(t *testing.T) {
+	var (
+		str = Schema{Type: TypeSet{TypeString}}
+		num = Schema{Type: TypeSet{TypeNumber}}
+		big = Schema{Minimum: ptr(json.Number("10"))}
+	)
+
+	tests := map[string]struct {
+		schema *Schema
+		tests  []validationTest
+	}{
+		"allOf": {schema: &Schema{AllOf: []Schema{num, big}}, tests: []validationTest{
+			{instance: `10`},
+			{instance: `"a"`, errs: ValidationErrors{{
+				Keyword:         "type",
+				KeywordLocation: "/allOf/0/type",
+				Message:         `have string, need ["number"]`,
+			}}},
+			{instance: `1`, errs: ValidationErrors{{
+				Keyword:         "minimum",
+				KeywordLocation: "/allOf/1/minimum",
+				Message:         "1 is less than 10",
+			}}},
+		}},
+		"anyOf": {schema: &Schema{AnyOf: []Schema{str, big}}, tests: []validationTest{
+			{instance: `"a"`},
+			{instance: `10`},
+			{instance: `1`, errs: ValidationErrors{
+				{Keyword: "anyOf", KeywordLocation: "/anyOf", Message: "not valid against any of the 2 subschemas"},
+				{Keyword: "type", KeywordLocation: "/anyOf/0/type", Message: `have integer, need ["string"]`},
+				{Keyword: "minimum", KeywordLocation: "/anyOf/1/minimum", Message: "1 is less than 10"},
+			}},
+		}},
+		"oneOf": {schema: &Schema{OneOf: []Schema{num, {Type: TypeSet{TypeInteger}}}}, tests: []validationTest{
+			{instance: `1.5`},
+			{instance: `1`, errs: ValidationErrors{{
+				Keyword:         "oneOf",
+				KeywordLocation: "/oneOf",
+				Message:         "valid against the subschemas [0 1], need exactly one",
+			}}},
+			{instance: `null`, errs: ValidationErrors{
+				{Keyword: "oneOf", KeywordLocation: "/oneOf", Message: "not valid against any of the 2 subschemas"},
+				{Keyword: "type", KeywordLocation: "/oneOf/0/type", Message: `have null, need ["number"]`},
+				{Keyword: "type", KeywordLocation: "/oneOf/1/type", Message: `have null, need ["integer"]`},
+			}},
+		}},
+		"not": {schema: &Schema{Not: &str}, tests: []validationTest{
+			{instance: `1`},
+			{instance: `"a"`, errs: ValidationErrors{{
+				Keyword:         "not",
+				KeywordLocation: "/not",
+				Message:         "valid against not",
+			}}},
+		}},
+		"false": {schema: &False, tests: []validationTest{
+			{instance: `{}`, errs: ValidationErrors{{
+				Keyword:         "not",
+				KeywordLocation: "/not",
+				Message:         "no value is valid against the false schema",
+			}}},
+		}},
+		"true": {schema: &True, tests: []validationTest{
+			{instance: `null`},
+		}},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			runValidationTests(t, test.schema, test.tests)
+		})
+	}
+}
+
+func TestSchema_Validate_Ref(t *testing.T) {
+	schema := &Schema{}
+	_ = json.Unmarshal([]byte(`{
+		"properties": {
+			"a": {"$ref": "#/$defs/a"},
+			"b": {"$ref": "https://example.com/b.json"}
+		},
+		"$defs": {"a": {"type": "string"}}
+	}`), schema)
+
+	runValidationTests(t, schema, []validationTest{
+		{instance: `{"a": "x"}`},
+		{instance: `{"a": 5}`, errs: ValidationErrors{{
+			Keyword:          "type",
+			KeywordLocation:  "/properties/a/$ref/type",
+			InstanceLocation: "/a",
+			Message:          `have integer, need ["string"]`,
+		}}},
+		// A reference to another document cannot be followed without a loader.
+		{instance: `{"b": 5}`, errs: ValidationErrors{{
+			Keyword:          "$ref",
+			KeywordLocation:  "/properties/b/$ref",
+			InstanceLocation: "/b",
+			Message:          `failed to resolve {"$ref": "https://example.com/b.json"} at "/properties/b": unable to locate non-embedded resource {"$id": "https://example.com/b.json"}: no loader configured`,
+		}}},
+	})
+}
+
+func TestSchema_ValidateResult(t *testing.T) {
+	schema := &Schema{
+		UniqueItems: ptr(true),
+		DependentRequired: map[string][]string{
+			"a": {"b"},
+		},
+	}
+
+	tests := []struct {
+		instance any
+		json     string
+	}{
+		{instance: []any{1.0, 2.0}, json: `{"valid":true}`},
+		{
+			instance: []any{1.0, 1.0},
+			json:     `{"valid":false,"errors":[{"keywordLocation":"/uniqueItems","instanceLocation":"","error":"items 0 and 1 are equal"}]}`,
+		},
+		{
+			instance: map[string]any{"a": 1.0},
+			json:     `{"valid":false,"errors":[{"keywordLocation":"/dependentRequired/a","instanceLocation":"","error":"property \"a\" requires missing properties [\"b\"]"}]}`,
+		},
+	}
+
+	for i, test := range tests {
+		result := schema.ValidateResult(test.instance)
+		if result.Valid != (schema.Validate(test.instance) == nil) {
+			t.Errorf("test #%d: result differs from Validate", i)
+		}
+
+		if b, err := json.Marshal(result); err != nil || string(b) != test.json {
+			t.Errorf("test #%d:\nhave %s\nneed %s", i, b, test.json)
+		}
+	}
+}
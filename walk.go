@@ -2,8 +2,8 @@ package jsonschema
 
 import (
 	"errors"
-	"fmt"
 	"path"
+	"strconv"
 )
 
 var (
@@ -22,8 +22,9 @@ var (
 type WalkFunc func(ptr string, schema *Schema) error
 
 // Walk walks the schema tree rooted at root, calling fn for each schema, including
-// root. The schemas are not walked in lexical order. The WalkFunc is first called
-// with the current schema and then walked if no error occurred.
+// root. The subschemas of a schema are walked in the order of Children, so the
+// order of the walk is deterministic. The WalkFunc is first called with the
+// current schema and then walked if no error occurred.
 //
 // If WalkFunc replaces the current schema, the new schema is walked:
 //
@@ -64,54 +65,120 @@ func Walk(root *Schema, fn WalkFunc) error {
 	return walk("", root, fn)
 }
 
-func iter(s *Schema, cont func(string, *Schema) bool) {
-	for keyword, schema := range map[string]*Schema{
-		"not":                   s.Not,
-		"if":                    s.If,
-		"then":                  s.Then,
-		"else":                  s.Else,
-		"items":                 s.Items,
-		"contains":              s.Contains,
-		"additionalProperties":  s.AdditionalProperties,
-		"propertyNames":         s.PropertyNames,
-		"unevaluatedItems":      s.UnevaluatedItems,
-		"unevaluatedProperties": s.UnevaluatedProperties,
-		"contentSchema":         s.ContentSchema,
-	} {
-		if schema == nil {
-			continue
-		}
-		if !cont(keyword, schema) {
-			return
+// SchemaRef is an immediate subschema of a schema, see Children.
+type SchemaRef struct {
+	// Keyword is the keyword containing the subschema, e.g. "properties".
+	Keyword string
+	// Key is the property name or index of the subschema for keywords containing
+	// multiple subschemas. It is empty for keywords with a single subschema.
+	Key string
+	// Schema is the subschema. A subschema of a keyword containing an object of
+	// schemas, e.g. properties, is a copy, since a map value is not addressable.
+	// It must be stored in the map of the parent to apply any modification.
+	Schema *Schema
+}
+
+// Segment returns the JSON pointer of the subschema relative to its parent, the
+// reference tokens are escaped:
+//
+//	properties/a~1b
+func (r SchemaRef) Segment() string {
+	if k := lookupKeyword(r.Keyword); k != nil && k.schema == nil {
+		return r.Keyword + "/" + escapeToken(r.Key)
+	}
+	return r.Keyword
+}
+
+// subschemaKeyword is a keyword containing subschemas. Only the accessor for the
+// kind of its value is set: a single schema, an array or an object of schemas.
+type subschemaKeyword struct {
+	name   string
+	schema func(s *Schema) *Schema
+	array  func(s *Schema) []Schema
+	object func(s *Schema) map[string]Schema
+}
+
+// subschemaKeywords are the keywords containing subschemas in the order of
+// Children.
+var subschemaKeywords = []subschemaKeyword{
+	{name: "additionalProperties", schema: func(s *Schema) *Schema { return s.AdditionalProperties }},
+	{name: "contains", schema: func(s *Schema) *Schema { return s.Contains }},
+	{name: "contentSchema", schema: func(s *Schema) *Schema { return s.ContentSchema }},
+	{name: "else", schema: func(s *Schema) *Schema { return s.Else }},
+	{name: "if", schema: func(s *Schema) *Schema { return s.If }},
+	{name: "items", schema: func(s *Schema) *Schema { return s.Items }},
+	{name: "not", schema: func(s *Schema) *Schema { return s.Not }},
+	{name: "propertyNames", schema: func(s *Schema) *Schema { return s.PropertyNames }},
+	{name: "then", schema: func(s *Schema) *Schema { return s.Then }},
+	{name: "unevaluatedItems", schema: func(s *Schema) *Schema { return s.UnevaluatedItems }},
+	{name: "unevaluatedProperties", schema: func(s *Schema) *Schema { return s.UnevaluatedProperties }},
+	{name: "allOf", array: func(s *Schema) []Schema { return s.AllOf }},
+	{name: "anyOf", array: func(s *Schema) []Schema { return s.AnyOf }},
+	{name: "oneOf", array: func(s *Schema) []Schema { return s.OneOf }},
+	{name: "prefixItems", array: func(s *Schema) []Schema { return s.PrefixItems }},
+	{name: "$defs", object: func(s *Schema) map[string]Schema { return s.Defs }},
+	{name: "dependentSchemas", object: func(s *Schema) map[string]Schema { return s.DependentSchemas }},
+	{name: "patternProperties", object: func(s *Schema) map[string]Schema { return s.PatternProperties }},
+	{name: "properties", object: func(s *Schema) map[string]Schema { return s.Properties }},
+}
+
+// lookupKeyword returns the keyword of subschemaKeywords with the name or nil.
+func lookupKeyword(name string) *subschemaKeyword {
+	for i := range subschemaKeywords {
+		if subschemaKeywords[i].name == name {
+			return &subschemaKeywords[i]
 		}
 	}
+	return nil
+}
 
-	for keyword, schemas := range map[string][]Schema{
-		"allOf":       s.AllOf,
-		"anyOf":       s.AnyOf,
-		"oneOf":       s.OneOf,
-		"prefixItems": s.PrefixItems,
-	} {
-		for i := range schemas {
-			if !cont(fmt.Sprintf("%s/%d", keyword, i), &schemas[i]) {
-				return
+// Children returns the immediate subschemas of s without descending into them.
+// The subschemas of keywords with a single subschema are returned first, followed
+// by the schemas of arrays in order and the schemas of objects sorted by key. The
+// keywords are ordered alphabetically within each group.
+func Children(s *Schema) []SchemaRef {
+	var refs []SchemaRef
+	for _, k := range subschemaKeywords {
+		switch {
+		case k.schema != nil:
+			if v := k.schema(s); v != nil {
+				refs = append(refs, SchemaRef{Keyword: k.name, Schema: v})
+			}
+		case k.array != nil:
+			schemas := k.array(s)
+			for i := range schemas {
+				refs = append(refs, SchemaRef{Keyword: k.name, Key: strconv.Itoa(i), Schema: &schemas[i]})
+			}
+		default:
+			schemas := k.object(s)
+			for _, key := range sortedKeys(schemas) {
+				v := schemas[key]
+				refs = append(refs, SchemaRef{Keyword: k.name, Key: key, Schema: &v})
 			}
 		}
 	}
+	return refs
+}
 
-	for keyword, schemas := range map[string]map[string]Schema{
-		"$defs":             s.Defs,
-		"dependentSchemas":  s.DependentSchemas,
-		"properties":        s.Properties,
-		"patternProperties": s.PatternProperties,
-	} {
-		for name := range schemas {
-			v := schemas[name]
-			if !cont(fmt.Sprintf("%s/%s", keyword, name), &v) {
-				schemas[name] = v
+// iter calls cont for the immediate subschemas of s in the order of Children,
+// until cont returns false. The subschemas of objects are written back to their
+// map after cont returned.
+func iter(s *Schema, cont func(string, *Schema) bool) {
+	for _, r := range Children(s) {
+		k := lookupKeyword(r.Keyword)
+		if k.schema != nil {
+			if !cont(r.Keyword, r.Schema) {
 				return
 			}
-			schemas[name] = v
+			continue
+		}
+
+		ok := cont(r.Keyword+"/"+r.Key, r.Schema)
+		if k.object != nil {
+			k.object(s)[r.Key] = *r.Schema
+		}
+		if !ok {
+			return
 		}
 	}
 }
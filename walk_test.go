@@ -233,6 +233,95 @@ func TestWalk(t *testing.T) {
 	}
 }
 
+// TestWalk_Order ensures the subschemas are walked in the same order on every
+// walk, the schemas of objects sorted by key.
+func TestWalk_Order(t *testing.T) {
+	s := Schema{
+		Defs: map[string]Schema{
+			"c": {},
+			"a": {Properties: map[string]Schema{"y": {}, "x": {}}},
+			"b": {},
+		},
+		Properties: map[string]Schema{
+			"name":  {},
+			"age":   {Not: &Schema{}},
+			"color": {},
+			"breed": {},
+		},
+		AllOf: []Schema{{}, {}},
+		Not:   &Schema{},
+		Items: &Schema{},
+	}
+	need := []string{
+		"/",
+		"/items",
+		"/not",
+		"/allOf/0",
+		"/allOf/1",
+		"/$defs/a",
+		"/$defs/a/properties/x",
+		"/$defs/a/properties/y",
+		"/$defs/b",
+		"/$defs/c",
+		"/properties/age",
+		"/properties/age/not",
+		"/properties/breed",
+		"/properties/color",
+		"/properties/name",
+	}
+
+	for i := 0; i < 20; i++ {
+		var have []string
+		if err := Walk(&s, func(ptr string, _ *Schema) error {
+			have = append(have, ptr)
+			return nil
+		}); err != nil {
+			t.Logf("unexpected error: %s", err)
+			t.FailNow()
+		}
+		if !slices.Equal(have, need) {
+			t.Logf("\nhave %v\nneed %v", have, need)
+			t.FailNow()
+		}
+	}
+}
+
+func TestChildren(t *testing.T) {
+	s := &Schema{
+		Not:         &Schema{Type: TypeSet{TypeNull}},
+		Items:       &True,
+		AllOf:       []Schema{{MinItems: ptr(1)}, {MaxItems: ptr(2)}},
+		PrefixItems: []Schema{True},
+		Properties: map[string]Schema{
+			"b":   {Type: TypeSet{TypeString}},
+			"a/b": {Type: TypeSet{TypeNumber}},
+		},
+		Defs: map[string]Schema{"x": {}},
+	}
+
+	var segments []string
+	for _, r := range Children(s) {
+		segments = append(segments, r.Segment())
+	}
+
+	expected := []string{"items", "not", "allOf/0", "allOf/1", "prefixItems/0", "$defs/x", "properties/a~1b", "properties/b"}
+	if !slices.Equal(segments, expected) {
+		t.Errorf("\nhave %v\nneed %v", segments, expected)
+	}
+
+	children := Children(s)
+	if r := children[2]; r.Keyword != "allOf" || r.Key != "0" || r.Schema != &s.AllOf[0] {
+		t.Errorf("expected reference to the first allOf schema, have %+v", r)
+	}
+	if r := children[6]; r.Keyword != "properties" || r.Key != "a/b" || !reflect.DeepEqual(*r.Schema, s.Properties["a/b"]) {
+		t.Errorf("expected property a/b, have %+v", r)
+	}
+
+	if children := Children(&Schema{Type: TypeSet{TypeObject}}); len(children) != 0 {
+		t.Errorf("expected no children, have %v", children)
+	}
+}
+
 func ExampleWalk() {
 	const p = `
 {